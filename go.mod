@@ -3,6 +3,6 @@ module github.com/nabeken/learn-pdf-with-go
 go 1.16
 
 require (
-	github.com/k0kubun/pp v3.0.1+incompatible // indirect
+	github.com/k0kubun/pp v3.0.1+incompatible
 	github.com/mattn/go-colorable v0.1.8 // indirect
 )
@@ -1 +1,1128 @@
 package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k0kubun/pp"
+
+	"github.com/nabeken/learn-pdf-with-go/pdf"
+)
+
+func main() {
+	// --repair rebuilds the cross-reference table by scanning the whole file
+	// --password decrypts an encrypted document with the given password
+	// --strict rejects cross-reference table entries which are not 20 bytes long
+	// --max-decoded-size limits the size of a decoded stream in bytes where a negative value means no limit
+	var args []string
+	var repair, strict bool
+	var password *string
+	var maxDecodedSize int64
+	for i := 1; i < len(os.Args); i++ {
+		switch arg := os.Args[i]; arg {
+		case "--repair":
+			repair = true
+		case "--strict":
+			strict = true
+		case "--password":
+			if i+1 < len(os.Args) {
+				i++
+				password = &os.Args[i]
+			}
+		case "--max-decoded-size":
+			if i+1 < len(os.Args) {
+				i++
+				n, err := strconv.ParseInt(os.Args[i], 10, 64)
+				if err != nil {
+					log.Fatalf("invalid --max-decoded-size: %v", err)
+				}
+				maxDecodedSize = n
+			}
+		default:
+			args = append(args, arg)
+		}
+	}
+
+	ra, size, closeInput, err := openInput(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeInput()
+
+	openDocument := func() *pdf.Document {
+		var doc *pdf.Document
+		var err error
+		switch {
+		case repair:
+			doc, err = pdf.NewRepairedDocument(ra, size)
+		case strict:
+			doc, err = pdf.NewStrictDocument(ra, size)
+		default:
+			doc, err = pdf.NewDocument(ra, size)
+		}
+		if err != nil {
+			log.Fatal(explain(err))
+		}
+		doc.MaxDecodedSize = maxDecodedSize
+		if password != nil {
+			if err := doc.Decrypt(*password); err != nil {
+				log.Fatal(explain(err))
+			}
+		}
+		return doc
+	}
+
+	switch args[1] {
+	case "show_trailer":
+		tr, err := pdf.ReadTrailer(ra, size)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		pp.Println(tr)
+		fmt.Println(string(tr.Raw))
+		return
+	case "validate":
+		tr, err := pdf.ReadTrailer(ra, size)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := tr.Validate(); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("ok")
+	case "show_xref_entry":
+		doc := openDocument()
+		entries := doc.XrefEntries()
+
+		number, generation := objectNumber(args, 2)
+		if number == -1 {
+			last := entries[len(entries)-1]
+			number, generation = last.Number, last.Generation
+		}
+
+		entry, err := doc.XrefEntry(number, generation)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !entry.InUse {
+			log.Fatalf("%d %d R is free", entry.Number, entry.Generation)
+		}
+
+		b, err := doc.ReadEntry(entry)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("%s\n", b)
+	case "show_object":
+		// show_object <number> [generation] prints the parsed object
+		// An object in the file is parsed as stored so that strings of an encrypted document are not decrypted.
+		doc := openDocument()
+
+		number, generation := objectNumber(args, 2)
+		entry, err := doc.XrefEntry(number, generation)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !entry.InUse {
+			log.Fatalf("%d %d R is free", entry.Number, entry.Generation)
+		}
+
+		var obj pdf.PDFObject
+		if entry.Compressed {
+			obj, err = doc.GetObject(number, generation)
+		} else {
+			var b []byte
+			b, err = doc.ReadEntry(entry)
+			if err == nil {
+				obj, err = pdf.ParseObject(b)
+			}
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		pp.Println(obj)
+	case "show_stream":
+		// show_stream <number> [generation] [--raw] [--hex] prints the decoded stream or its hex dump
+		// --raw prints the body as stored without decoding the filters. It is still decrypted.
+		doc := openDocument()
+
+		var raw, hex bool
+		for _, arg := range args[3:] {
+			switch arg {
+			case "--raw":
+				raw = true
+			case "--hex":
+				hex = true
+			}
+		}
+
+		number, generation := objectNumber(args, 2)
+		entry, err := doc.XrefEntry(number, generation)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		obj, err := doc.Resolve(pdf.PDFRef{Number: entry.Number, Generation: entry.Generation})
+		if err != nil {
+			log.Fatal(err)
+		}
+		stream, ok := obj.(pdf.PDFStream)
+		if !ok {
+			log.Fatalf("%d %d R is not a stream", number, generation)
+		}
+		dict := stream.Dict
+
+		b, err := doc.ReadStreamBody(entry, dict)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if !raw {
+			// 0 is the default limit of the document
+			limit := doc.MaxDecodedSize
+			if limit == 0 {
+				limit = pdf.DefaultMaxDecodedSize
+			}
+			b, err = pdf.DecodeStreamWithLimit(dict, b, limit)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		if hex {
+			if err := pdf.HexDump(os.Stdout, b, 0); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+		fmt.Printf("%s", b)
+	case "show_catalog":
+		doc := openDocument()
+
+		catalog, err := doc.Catalog()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		pp.Println(catalog)
+	case "count_pages":
+		doc := openDocument()
+
+		pages, err := doc.Pages()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println(len(pages))
+	case "page_text":
+		// page_text <pages> where pages is a page range such as 1-3,5 and the first page is 1
+		// pages are separated by a form feed
+		doc := openDocument()
+
+		numbers, pages, err := selectPages(doc, args[2])
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i, page := range pages {
+			text, err := doc.PageText(page)
+			if err != nil {
+				log.Fatalf("page %d: %v", numbers[i], err)
+			}
+			if i > 0 {
+				fmt.Print("\f")
+			}
+			fmt.Println(text)
+		}
+	case "pagesize":
+		// pagesize <page> where the first page is 1
+		doc := openDocument()
+
+		pages, err := doc.Pages()
+		if err != nil {
+			log.Fatal(err)
+		}
+		pageN, _ := strconv.Atoi(args[2])
+		if pageN < 1 || pageN > len(pages) {
+			log.Fatalf("page %d is out of range (1-%d)", pageN, len(pages))
+		}
+
+		for _, box := range []string{"MediaBox", "CropBox"} {
+			rect, err := doc.PageBox(pages[pageN-1], box)
+			if err != nil {
+				log.Fatal(err)
+			}
+			// 1 point is 1/72 inch
+			const mmPerPoint = 25.4 / 72
+			fmt.Printf(
+				"%s: %g x %g pt (%.1f x %.1f mm)\n",
+				box, rect.Width(), rect.Height(), rect.Width()*mmPerPoint, rect.Height()*mmPerPoint,
+			)
+		}
+		rotate, err := doc.PageRotation(pages[pageN-1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Rotate: %d\n", rotate)
+	case "fonts":
+		// fonts <pages> where pages is a page range such as 1-3,5 and the first page is 1
+		// each page is headed by its number when more than one page is selected
+		doc := openDocument()
+
+		numbers, pages, err := selectPages(doc, args[2])
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i, page := range pages {
+			fonts, err := doc.PageFonts(page)
+			if err != nil {
+				log.Fatalf("page %d: %v", numbers[i], err)
+			}
+			if len(pages) > 1 {
+				fmt.Printf("page %d\n", numbers[i])
+			}
+
+			names := make([]string, 0, len(fonts))
+			for name := range fonts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				font := fonts[name]
+				subtype, _ := font["Subtype"].(pdf.PDFName)
+				baseFont, _ := font["BaseFont"].(pdf.PDFName)
+				line := fmt.Sprintf("%s %s %s", name, string(subtype), string(baseFont))
+				if subtype == "Type0" {
+					if descendant, err := doc.DescendantFont(font); err == nil {
+						subtype, _ := descendant["Subtype"].(pdf.PDFName)
+						baseFont, _ := descendant["BaseFont"].(pdf.PDFName)
+						line += fmt.Sprintf(" (%s %s)", string(subtype), string(baseFont))
+					}
+				}
+				fmt.Println(line)
+			}
+		}
+	case "extract_files":
+		// extract_files [--out dir] writes embedded files into dir
+		doc := openDocument()
+
+		dir := "."
+		for i := 2; i+1 < len(args); i++ {
+			if args[i] == "--out" {
+				dir = args[i+1]
+			}
+		}
+
+		files, err := doc.EmbeddedFiles()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, file := range files {
+			// never write outside dir
+			path := filepath.Join(dir, filepath.Base(filepath.Clean("/"+file.FileName)))
+			if err := extractFile(file, path); err != nil {
+				log.Fatal(err)
+			}
+			fmt.Println(path)
+		}
+	case "info":
+		doc := openDocument()
+
+		info, err := doc.Info()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		date := func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format(time.RFC3339)
+		}
+		fmt.Printf("Title: %s\n", info.Title)
+		fmt.Printf("Author: %s\n", info.Author)
+		fmt.Printf("Subject: %s\n", info.Subject)
+		fmt.Printf("Keywords: %s\n", info.Keywords)
+		fmt.Printf("Creator: %s\n", info.Creator)
+		fmt.Printf("Producer: %s\n", info.Producer)
+		fmt.Printf("CreationDate: %s\n", date(info.CreationDate))
+		fmt.Printf("ModDate: %s\n", date(info.ModDate))
+	case "xmp":
+		doc := openDocument()
+
+		b, err := doc.XMP()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s", b)
+	case "outlines":
+		doc := openDocument()
+
+		root, err := doc.Outlines()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if root != nil {
+			printOutline(doc, root.Children, 0)
+		}
+	case "annots":
+		// annots <page> where the first page is 1
+		doc := openDocument()
+
+		pages, err := doc.Pages()
+		if err != nil {
+			log.Fatal(err)
+		}
+		pageN, _ := strconv.Atoi(args[2])
+		if pageN < 1 || pageN > len(pages) {
+			log.Fatalf("page %d is out of range (1-%d)", pageN, len(pages))
+		}
+
+		annots, err := doc.Annotations(pages[pageN-1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, annot := range annots {
+			target := ""
+			if annot.DestPage >= 0 {
+				target = fmt.Sprintf(" -> page %d", annot.DestPage+1)
+			} else if annot.URI != "" {
+				target = " -> " + annot.URI
+			}
+			fmt.Printf("%s %v%s\n", string(annot.Subtype), annot.Rect, target)
+		}
+	case "version":
+		doc := openDocument()
+
+		version, err := doc.Version()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(version)
+	case "dump_json":
+		// dump_json [decoded] includes decoded stream bodies
+		doc := openDocument()
+
+		decoded := len(args) > 2 && args[2] == "decoded"
+		if err := doc.DumpJSON(os.Stdout, decoded); err != nil {
+			log.Fatal(err)
+		}
+	case "file_id":
+		tr, err := pdf.ReadTrailer(ra, size)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		id, err := tr.ID()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("original: %x\n", id[0])
+		fmt.Printf("current:  %x\n", id[1])
+	case "show_objstm":
+		doc := openDocument()
+
+		objStmNum, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		objs, err := doc.ObjStmContents(objStmNum)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		numbers := make([]int64, 0, len(objs))
+		for number := range objs {
+			numbers = append(numbers, number)
+		}
+		sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+		for _, number := range numbers {
+			fmt.Printf("%d 0 obj\n%s\nendobj\n", number, objs[number])
+		}
+	case "linearization":
+		doc := openDocument()
+
+		linearized, dict, err := doc.IsLinearized()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if dict == nil {
+			fmt.Println("not linearized")
+			return
+		}
+		if !linearized {
+			fmt.Println("not linearized (updated after linearization)")
+		} else {
+			fmt.Println("linearized")
+		}
+		if !doc.Seekable() {
+			fmt.Println("the input is read into memory as a whole; the linearization does not speed up loading")
+		}
+		fmt.Printf("file length (/L): %s\n", dict["L"])
+		fmt.Printf("pages (/N): %s\n", dict["N"])
+		fmt.Printf("first page object (/O): %s\n", dict["O"])
+		fmt.Printf("end of first page (/E): %s\n", dict["E"])
+	case "revisions":
+		// revisions [number] lists revisions or the entries of the object in every revision
+		doc := openDocument()
+
+		if len(args) > 2 {
+			number, err := strconv.ParseInt(args[2], 10, 64)
+			if err != nil {
+				log.Fatal(err)
+			}
+			history, err := doc.ObjectRevisions(number)
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, ent := range history {
+				switch {
+				case !ent.InUse:
+					fmt.Printf("%d %d R: free\n", ent.Number, ent.Generation)
+				case ent.Compressed:
+					fmt.Printf("%d %d R: in object stream %d at %d\n", ent.Number, ent.Generation, ent.StreamNumber, ent.StreamIndex)
+				default:
+					fmt.Printf("%d %d R: at %d\n", ent.Number, ent.Generation, ent.ByteOffset)
+				}
+			}
+			return
+		}
+
+		revisions, err := doc.Revisions()
+		if err != nil {
+			log.Fatal(err)
+		}
+		timestamps, err := doc.RevisionTimestamps()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i, rev := range revisions {
+			fmt.Printf("revision %d: xref at %d with %d entries", i, rev.XrefOffset, len(rev.Entries))
+			if !timestamps[i].IsZero() {
+				fmt.Printf(", modified %s", timestamps[i].Format(time.RFC3339))
+			}
+			fmt.Println()
+		}
+	case "dump_at":
+		// dump_at <offset> [length] prints raw bytes at the offset
+		offset, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			log.Fatal(err)
+		}
+		length := int64(512)
+		if len(args) > 3 {
+			length, err = strconv.ParseInt(args[3], 10, 64)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		if err := pdf.DumpAt(os.Stdout, ra, offset, length); err != nil {
+			log.Fatal(err)
+		}
+	case "stats":
+		doc := openDocument()
+
+		stats, err := doc.Stats()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("objects: %d\n", stats.Objects)
+		fmt.Printf("free entries: %d\n", stats.Free)
+		fmt.Printf("highest object number: %d\n", stats.MaxObjectNumber)
+		fmt.Printf("object streams: %d\n", stats.ObjectStreams)
+		fmt.Printf("streams: %d (%d bytes, %d bytes decoded)\n", stats.Streams, stats.StreamBytes, stats.DecodedStreamBytes)
+		if stats.Errors > 0 {
+			fmt.Printf("unreadable objects: %d\n", stats.Errors)
+		}
+
+		types := make([]string, 0, len(stats.Types))
+		for typ := range stats.Types {
+			types = append(types, typ)
+		}
+		sort.Strings(types)
+		for _, typ := range types {
+			fmt.Printf("  %s: %d\n", typ, stats.Types[typ])
+		}
+	case "orphans":
+		doc := openDocument()
+
+		orphans, err := doc.Orphans()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, number := range orphans {
+			fmt.Println(number)
+		}
+	case "gc":
+		// gc <out> writes only reachable objects into out
+		doc := openDocument()
+
+		out, err := os.Create(args[2])
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer out.Close()
+
+		if err := doc.WriteGC(out); err != nil {
+			log.Fatal(err)
+		}
+	case "set_info":
+		// set_info <out> <key> <value> writes an incremental update changing the document information into out
+		doc := openDocument()
+
+		infoRef, ok := doc.TrailerDict()["Info"].(pdf.PDFRef)
+		if !ok {
+			log.Fatal("trailer must have /Info as an indirect reference")
+		}
+		info, err := doc.GetDict(infoRef.Number, infoRef.Generation)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		updated := pdf.PDFDict{}
+		for k, v := range info {
+			updated[k] = v
+		}
+		updated[args[3]] = pdf.EncodeTextString(args[4])
+
+		out, err := os.Create(args[2])
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer out.Close()
+
+		if err := doc.AppendUpdate(out, map[int64]pdf.PDFObject{infoRef.Number: updated}); err != nil {
+			log.Fatal(err)
+		}
+	case "extract_images":
+		// extract_images [--out dir] [--pages pages] writes images of the pages (all pages by default) into dir as PNG or JPEG
+		// where pages is a page range such as 1-3,5 and the first page is 1
+		doc := openDocument()
+
+		dir, spec := ".", "1-"
+		for i := 2; i+1 < len(args); i++ {
+			switch args[i] {
+			case "--out":
+				dir = args[i+1]
+			case "--pages":
+				spec = args[i+1]
+			}
+		}
+
+		numbers, pages, err := selectPages(doc, spec)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i, page := range pages {
+			images, err := doc.PageImages(page)
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, img := range images {
+				line := fmt.Sprintf("page %d /%s %dx%d", numbers[i], img.Name, img.Width, img.Height)
+				if img.ImageMask {
+					line += " image mask"
+				} else {
+					line += fmt.Sprintf(" %s %d bpc", string(img.ColorSpace), img.BitsPerComponent)
+				}
+				if img.SMask != (pdf.PDFRef{}) {
+					line += fmt.Sprintf(" smask %d %d R", img.SMask.Number, img.SMask.Generation)
+				}
+
+				// never write outside dir
+				base := filepath.Join(dir, filepath.Base(filepath.Clean(fmt.Sprintf("/p%d-%s", numbers[i], img.Name))))
+				path, err := extractImage(img, base)
+				if err != nil {
+					line += fmt.Sprintf(": skipped: %v", err)
+				} else {
+					line += ": " + path
+				}
+				fmt.Println(line)
+			}
+		}
+	case "extract_object":
+		// extract_object <number> [generation] [--out file] writes the decoded stream or the object syntax to file or stdout
+		doc := openDocument()
+
+		out := ""
+		for i := 3; i+1 < len(args); i++ {
+			if args[i] == "--out" {
+				out = args[i+1]
+			}
+		}
+
+		number, generation := objectNumber(args, 2)
+		entry, err := doc.XrefEntry(number, generation)
+		if err != nil {
+			log.Fatal(err)
+		}
+		obj, err := doc.Resolve(pdf.PDFRef{Number: entry.Number, Generation: entry.Generation})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var r io.Reader
+		if _, ok := obj.(pdf.PDFStream); ok {
+			// the stream is decoded as it is written with the limit of the decoded size
+			sr, err := doc.OpenStream(entry)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer sr.Close()
+			r = sr
+		} else {
+			r = strings.NewReader(fmt.Sprint(obj) + "\n")
+		}
+
+		w := io.Writer(os.Stdout)
+		if out != "" {
+			f, err := os.Create(out)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer f.Close()
+			w = f
+		}
+		n, err := io.Copy(w, r)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if out != "" {
+			fmt.Printf("wrote %d bytes to %s\n", n, out)
+		} else {
+			fmt.Fprintf(os.Stderr, "wrote %d bytes\n", n)
+		}
+	case "extract_fonts":
+		// extract_fonts [--out dir] writes embedded font programs of all pages into dir
+		doc := openDocument()
+
+		dir := "."
+		for i := 2; i+1 < len(args); i++ {
+			if args[i] == "--out" {
+				dir = args[i+1]
+			}
+		}
+
+		pages, err := doc.Pages()
+		if err != nil {
+			log.Fatal(err)
+		}
+		// fonts are usually shared by pages and subsets have unique names
+		seen := map[string]bool{}
+		for _, page := range pages {
+			fonts, err := doc.PageFonts(page)
+			if err != nil {
+				log.Fatal(err)
+			}
+			names := make([]string, 0, len(fonts))
+			for name := range fonts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, resName := range names {
+				name, data, ext, err := doc.ExtractFont(fonts[resName])
+				if errors.Is(err, pdf.ErrFontNotEmbedded) || seen[name] {
+					continue
+				}
+				if err != nil {
+					log.Fatal(err)
+				}
+				seen[name] = true
+
+				// never write outside dir
+				path := filepath.Join(dir, filepath.Base(filepath.Clean("/"+name+ext)))
+				if err := os.WriteFile(path, data, 0644); err != nil {
+					log.Fatal(err)
+				}
+				fmt.Printf("%s: %d bytes\n", path, len(data))
+			}
+		}
+	case "font_widths":
+		// font_widths <page> <font> prints the glyph widths of the font resource on the page
+		doc := openDocument()
+
+		pages, err := doc.Pages()
+		if err != nil {
+			log.Fatal(err)
+		}
+		pageN, _ := strconv.Atoi(args[2])
+		if pageN < 1 || pageN > len(pages) {
+			log.Fatalf("page %d is out of range (1-%d)", pageN, len(pages))
+		}
+
+		fonts, err := doc.PageFonts(pages[pageN-1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		font, ok := fonts[strings.TrimPrefix(args[3], "/")]
+		if !ok {
+			log.Fatalf("font %s is not found on page %d", args[3], pageN)
+		}
+
+		widths, err := doc.FontWidths(font)
+		if err != nil {
+			log.Fatal(err)
+		}
+		dw, err := doc.FontDefaultWidth(font)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		codes := make([]int, 0, len(widths))
+		for code := range widths {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		fmt.Printf("default: %g\n", dw)
+		for _, code := range codes {
+			fmt.Printf("%d: %g\n", code, widths[code])
+		}
+	case "text_runs":
+		// text_runs <page> prints text with the position and the font size where the first page is 1
+		doc := openDocument()
+
+		pages, err := doc.Pages()
+		if err != nil {
+			log.Fatal(err)
+		}
+		pageN, _ := strconv.Atoi(args[2])
+		if pageN < 1 || pageN > len(pages) {
+			log.Fatalf("page %d is out of range (1-%d)", pageN, len(pages))
+		}
+
+		runs, err := doc.PageTextRuns(pages[pageN-1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, run := range runs {
+			fmt.Printf("%8.2f %8.2f %6.2f %q\n", run.X, run.Y, run.FontSize, run.Text)
+		}
+	case "diff":
+		// diff <other> prints object numbers changed, added or removed in other
+		doc := openDocument()
+
+		other, err := pdf.OpenFile(args[2])
+		if err != nil {
+			log.Fatal(explain(err))
+		}
+		defer other.Close()
+
+		hashes, err := doc.ObjectHashes()
+		if err != nil {
+			log.Fatal(err)
+		}
+		otherHashes, err := other.ObjectHashes()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		numbers := make([]int64, 0, len(hashes)+len(otherHashes))
+		for number := range hashes {
+			numbers = append(numbers, number)
+		}
+		for number := range otherHashes {
+			if _, ok := hashes[number]; !ok {
+				numbers = append(numbers, number)
+			}
+		}
+		sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+		differs := false
+		for _, number := range numbers {
+			h, ok := hashes[number]
+			otherH, otherOK := otherHashes[number]
+			switch {
+			case !ok:
+				fmt.Printf("added %d\n", number)
+			case !otherOK:
+				fmt.Printf("removed %d\n", number)
+			case h != otherH:
+				fmt.Printf("changed %d\n", number)
+			default:
+				continue
+			}
+			differs = true
+		}
+		if differs {
+			os.Exit(1)
+		}
+	case "tree":
+		// tree [--depth n] prints the indirect objects reachable from /Root as a tree
+		doc := openDocument()
+
+		depth := 0
+		for i := 2; i+1 < len(args); i++ {
+			if args[i] == "--depth" {
+				depth, _ = strconv.Atoi(args[i+1])
+			}
+		}
+
+		root, err := doc.RefGraph(depth)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printRefNode(root, 0)
+	case "page_labels":
+		// page_labels prints the label of each page after the page number starting from 1
+		doc := openDocument()
+
+		labels, err := doc.PageLabels()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i, label := range labels {
+			fmt.Printf("%d: %s\n", i+1, label)
+		}
+	case "form_fields":
+		// form_fields prints the name, the type and the value of each terminal field
+		doc := openDocument()
+
+		fields, err := doc.FormFields()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, field := range fields {
+			value := ""
+			if field.Value != nil {
+				value = fmt.Sprint(field.Value)
+			}
+			fmt.Printf("%s\t%s\t%s\n", field.Name, string(field.Type), value)
+		}
+	case "form_values":
+		// form_values prints the value of each field as text in JSON
+		doc := openDocument()
+
+		values, err := doc.FormValues()
+		if err != nil {
+			log.Fatal(err)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(values); err != nil {
+			log.Fatal(err)
+		}
+	case "signatures":
+		// signatures prints the signatures and the bytes they cover without verifying them
+		doc := openDocument()
+
+		sigs, err := doc.Signatures()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, sig := range sigs {
+			fmt.Printf("signature %s\n", sig.Field)
+			fmt.Printf("  Filter: %s\n", string(sig.Filter))
+			fmt.Printf("  SubFilter: %s\n", string(sig.SubFilter))
+			fmt.Printf("  ByteRange: %v\n", sig.ByteRange)
+			fmt.Printf("  Name: %s\n", sig.Name)
+			if !sig.Time.IsZero() {
+				fmt.Printf("  Time: %s\n", sig.Time.Format(time.RFC3339))
+			}
+			if sig.CoversFile {
+				fmt.Printf("  SignedEnd: %d (whole file)\n", sig.SignedEnd)
+			} else {
+				fmt.Printf("  SignedEnd: %d\n", sig.SignedEnd)
+			}
+			fmt.Printf("  UpdatesAfter: %d\n", sig.UpdatesAfter)
+		}
+	case "verify_lengths":
+		doc := openDocument()
+
+		errs := doc.VerifyStreamLengths()
+		for _, lerr := range errs {
+			fmt.Println(lerr)
+		}
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		fmt.Println("ok")
+	case "verify_xref":
+		doc := openDocument()
+
+		errs := doc.VerifyXref()
+		for _, xerr := range errs {
+			fmt.Println(xerr)
+		}
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		fmt.Println("ok")
+	}
+}
+
+// explain adds a hint to errors which the user can work around.
+func explain(err error) string {
+	switch {
+	case errors.Is(err, pdf.ErrBadStartxref), errors.Is(err, pdf.ErrNotXref), errors.Is(err, pdf.ErrNoEntry):
+		return fmt.Sprintf("%v\nthe cross-reference table looks broken; try --repair", err)
+	case errors.Is(err, pdf.ErrWrongPassword):
+		return fmt.Sprintf("%v\ngive the user or the owner password with --password", err)
+	case errors.Is(err, pdf.ErrUnsupportedEncryption), errors.Is(err, pdf.ErrUnsupportedFilter):
+		return fmt.Sprintf("%v\nthe file uses a feature which is not supported yet", err)
+	}
+	return err.Error()
+}
+
+// printRefNode prints the node as "path -> N G /Type" and its children indented.
+func printRefNode(node *pdf.RefNode, indent int) {
+	line := fmt.Sprintf("%s%s -> %d %d", strings.Repeat("  ", indent), node.Path, node.Ref.Number, node.Ref.Generation)
+	switch {
+	case node.Seen:
+		line += " (seen)"
+	case node.Err != nil:
+		line += fmt.Sprintf(" (%v)", node.Err)
+	default:
+		if node.Type != "" {
+			line += " " + node.Type.String()
+		}
+		if node.Truncated {
+			line += " ..."
+		}
+	}
+	fmt.Println(line)
+
+	for _, child := range node.Children {
+		printRefNode(child, indent+1)
+	}
+}
+
+// selectPages returns the page numbers selected by the page range spec and the pages.
+func selectPages(doc *pdf.Document, spec string) ([]int, []pdf.PDFDict, error) {
+	pages, err := doc.Pages()
+	if err != nil {
+		return nil, nil, err
+	}
+	numbers, err := pdf.ParsePageRange(spec, len(pages))
+	if err != nil {
+		return nil, nil, err
+	}
+	selected := make([]pdf.PDFDict, len(numbers))
+	for i, n := range numbers {
+		selected[i] = pages[n-1]
+	}
+	return numbers, selected, nil
+}
+
+// objectNumber returns the object number at args[i] and the generation following it.
+// The generation is 0 unless it is given.
+func objectNumber(args []string, i int) (int64, int) {
+	number, _ := strconv.ParseInt(args[i], 10, 64)
+	generation := 0
+	if i+1 < len(args) {
+		if g, err := strconv.Atoi(args[i+1]); err == nil {
+			generation = g
+		}
+	}
+	return number, generation
+}
+
+// printOutline prints titles with the page number (the first page is 1) if the destination is resolved.
+func printOutline(doc *pdf.Document, nodes []*pdf.OutlineNode, depth int) {
+	for _, node := range nodes {
+		var dest pdf.PDFObject = node.Dest
+		if dest == nil && node.Action != nil {
+			dest = node.Action
+		}
+
+		page := ""
+		if dest != nil {
+			if i, _, err := doc.ResolveDest(dest); err == nil {
+				page = fmt.Sprintf(" (page %d)", i+1)
+			}
+		}
+		fmt.Printf("%s%s%s\n", strings.Repeat("  ", depth), node.Title, page)
+		printOutline(doc, node.Children, depth+1)
+	}
+}
+
+func extractFile(file pdf.EmbeddedFile, path string) error {
+	r, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("unable to open %q: %w", file.Name, err)
+	}
+	defer r.Close()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("unable to extract %q: %w", file.Name, err)
+	}
+	return f.Close()
+}
+
+// extractImage writes img to base with the extension of the format and returns the path.
+func extractImage(img pdf.Image, base string) (string, error) {
+	if img.IsJPEG() {
+		b, err := img.JPEG()
+		if err != nil {
+			return "", err
+		}
+		// CMYK JPEG looks inverted in many viewers so it is converted to RGB PNG
+		if cfg, err := jpeg.DecodeConfig(bytes.NewReader(b)); err != nil || cfg.ColorModel != color.CMYKModel {
+			path := base + ".jpg"
+			return path, os.WriteFile(path, b, 0644)
+		}
+	}
+
+	decoded, err := img.Decode()
+	if err != nil {
+		return "", err
+	}
+	path := base + ".png"
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := png.Encode(f, decoded); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// openInput opens a PDF file. The path "-" reads the whole stdin into memory.
+func openInput(path string) (io.ReaderAt, int64, func() error, error) {
+	if path == "-" {
+		ra, size, err := pdf.BufferInput(os.Stdin)
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("unable to read stdin: %w", err)
+		}
+		return ra, size, func() error { return nil }, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	fstat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, nil, err
+	}
+
+	return f, fstat.Size(), f.Close, nil
+}
@@ -0,0 +1,103 @@
+package pdf
+
+import (
+	"fmt"
+)
+
+// PageAnnotations returns the annotation dictionaries in /Annots of page.
+// 12.5 Annotations
+func (d *Document) PageAnnotations(page PDFDict) ([]PDFDict, error) {
+	if _, ok := page["Annots"]; !ok {
+		return nil, nil
+	}
+	obj, err := d.Resolve(page["Annots"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /Annots: %w", err)
+	}
+	annots, ok := obj.(PDFArray)
+	if !ok {
+		return nil, fmt.Errorf("/Annots must be an array but got %T", obj)
+	}
+
+	dicts := make([]PDFDict, 0, len(annots))
+	for i := range annots {
+		dict, err := d.resolveDict(annots[i])
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve annotation %d: %w", i, err)
+		}
+		dicts = append(dicts, dict)
+	}
+	return dicts, nil
+}
+
+// Annotation is a classified annotation.
+type Annotation struct {
+	// Subtype is such as Link, Text, Highlight and Widget
+	Subtype PDFName
+	// Rect is the annotation rectangle in default user space or nil
+	Rect []float64
+	Dict PDFDict
+
+	// for links, DestPage is the zero-based page index of the destination or -1
+	// and URI is the target of a URI action
+	DestPage int
+	URI      string
+}
+
+// Annotations returns annotations of page with the target of links.
+// A link whose destination cannot be resolved has DestPage -1.
+func (d *Document) Annotations(page PDFDict) ([]Annotation, error) {
+	dicts, err := d.PageAnnotations(page)
+	if err != nil {
+		return nil, err
+	}
+
+	annots := make([]Annotation, 0, len(dicts))
+	for _, dict := range dicts {
+		annot := Annotation{Dict: dict, DestPage: -1}
+		annot.Subtype, _ = dict["Subtype"].(PDFName)
+
+		if rect, err := d.Resolve(dict["Rect"]); err == nil {
+			if arr, ok := rect.(PDFArray); ok && len(arr) == 4 {
+				for _, v := range arr {
+					f, _ := toFloat(v)
+					annot.Rect = append(annot.Rect, f)
+				}
+			}
+		}
+
+		if annot.Subtype == "Link" {
+			d.linkTarget(&annot)
+		}
+		annots = append(annots, annot)
+	}
+	return annots, nil
+}
+
+// 12.5.6.5 Link Annotations
+func (d *Document) linkTarget(annot *Annotation) {
+	if dest, ok := annot.Dict["Dest"]; ok {
+		if i, _, err := d.ResolveDest(dest); err == nil {
+			annot.DestPage = i
+		}
+		return
+	}
+
+	action, err := d.resolveDict(annot.Dict["A"])
+	if err != nil {
+		return
+	}
+	switch s, _ := action["S"].(PDFName); s {
+	case "GoTo":
+		if i, _, err := d.ResolveDest(action); err == nil {
+			annot.DestPage = i
+		}
+	case "URI":
+		// 12.6.4.7 URI Actions
+		if uri, err := d.Resolve(action["URI"]); err == nil {
+			if b, ok := uri.(PDFString); ok {
+				annot.URI = string(b)
+			}
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// cmapCode is a character code with its length in bytes since <01> and <0001> are different codes.
+type cmapCode struct {
+	code uint32
+	n    int
+}
+
+type codespaceRange struct {
+	low, high uint32
+	n         int
+}
+
+// toUnicodeCMap maps character codes to Unicode.
+// 9.10.3 ToUnicode CMaps
+type toUnicodeCMap struct {
+	codespaces []codespaceRange
+	mappings   map[cmapCode]string
+}
+
+// ParseToUnicodeCMap parses a ToUnicode CMap and returns the mapping from character codes to Unicode.
+// Codes mapped to more than one character such as a ligature are not included.
+func ParseToUnicodeCMap(stream []byte) (map[uint32]rune, error) {
+	cmap, err := parseToUnicodeCMap(stream)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[uint32]rune, len(cmap.mappings))
+	for code, s := range cmap.mappings {
+		if utf8.RuneCountInString(s) == 1 {
+			r, _ := utf8.DecodeRuneInString(s)
+			m[code.code] = r
+		}
+	}
+	return m, nil
+}
+
+func parseToUnicodeCMap(b []byte) (*toUnicodeCMap, error) {
+	cmap := &toUnicodeCMap{mappings: map[cmapCode]string{}}
+
+	lex := NewLexer(bytes.NewReader(b))
+	next := func() (Token, error) {
+		tok, err := lex.Next()
+		if err != nil {
+			return Token{}, fmt.Errorf("unable to read the CMap: %w", err)
+		}
+		return tok, nil
+	}
+
+	for {
+		tok, err := next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case tok.Kind == TokenEOF:
+			return cmap, nil
+		case tok.Is(TokenKeyword, "begincodespacerange"):
+			for {
+				low, err := next()
+				if err != nil {
+					return nil, err
+				}
+				if low.Is(TokenKeyword, "endcodespacerange") {
+					break
+				}
+				high, err := next()
+				if err != nil {
+					return nil, err
+				}
+				if low.Kind != TokenHexString || high.Kind != TokenHexString || len(low.Value) != len(high.Value) {
+					return nil, errors.New("codespacerange must have pairs of hex strings of the same length")
+				}
+				cmap.codespaces = append(cmap.codespaces, codespaceRange{
+					low:  cmapCodeOf(low.Value),
+					high: cmapCodeOf(high.Value),
+					n:    len(low.Value),
+				})
+			}
+		case tok.Is(TokenKeyword, "beginbfchar"):
+			for {
+				src, err := next()
+				if err != nil {
+					return nil, err
+				}
+				if src.Is(TokenKeyword, "endbfchar") {
+					break
+				}
+				dst, err := next()
+				if err != nil {
+					return nil, err
+				}
+				if src.Kind != TokenHexString || dst.Kind != TokenHexString {
+					return nil, errors.New("bfchar must have pairs of hex strings")
+				}
+				cmap.mappings[cmapCode{cmapCodeOf(src.Value), len(src.Value)}] = utf16BEString(dst.Value)
+			}
+		case tok.Is(TokenKeyword, "beginbfrange"):
+			for {
+				low, err := next()
+				if err != nil {
+					return nil, err
+				}
+				if low.Is(TokenKeyword, "endbfrange") {
+					break
+				}
+				high, err := next()
+				if err != nil {
+					return nil, err
+				}
+				if low.Kind != TokenHexString || high.Kind != TokenHexString {
+					return nil, errors.New("bfrange must start with two hex strings")
+				}
+				if err := cmap.addRange(low.Value, high.Value, next); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+// addRange adds mappings of a bfrange from low to high. The destination is read by next and is
+// either a hex string whose last byte is incremented over the range or an array of hex strings.
+func (cmap *toUnicodeCMap) addRange(low, high string, next func() (Token, error)) error {
+	n := len(low)
+	lo, hi := cmapCodeOf(low), cmapCodeOf(high)
+	if hi < lo || hi-lo > 0xffff {
+		return fmt.Errorf("invalid bfrange <%x> <%x>", low, high)
+	}
+
+	dst, err := next()
+	if err != nil {
+		return err
+	}
+
+	switch dst.Kind {
+	case TokenHexString:
+		units := utf16Units(dst.Value)
+		if len(units) == 0 {
+			return errors.New("bfrange destination must not be empty")
+		}
+		// the offset is counted instead of the code which wraps around at <FFFFFFFF>
+		for i := uint32(0); i <= hi-lo; i++ {
+			u := append([]uint16(nil), units...)
+			u[len(u)-1] += uint16(i)
+			cmap.mappings[cmapCode{lo + i, n}] = string(utf16.Decode(u))
+		}
+		return nil
+	case TokenArrayBegin:
+		for i := uint32(0); ; i++ {
+			tok, err := next()
+			if err != nil {
+				return err
+			}
+			if tok.Kind == TokenArrayEnd {
+				return nil
+			}
+			if tok.Kind != TokenHexString {
+				return errors.New("bfrange destination array must have hex strings")
+			}
+			if i <= hi-lo {
+				cmap.mappings[cmapCode{lo + i, n}] = utf16BEString(tok.Value)
+			}
+		}
+	}
+	return fmt.Errorf("bfrange destination must be a hex string or an array but got %s", dst.Kind)
+}
+
+// decode maps codes in s to Unicode. A code is as long as a codespace range containing it, or
+// as long as a mapped code when the CMap has no codespace ranges. fallback is called for an unmapped code.
+func (cmap *toUnicodeCMap) decode(s []byte, fallback func(code []byte) string) string {
+	var out []byte
+	for i := 0; i < len(s); {
+		n := cmap.codeLength(s[i:])
+		code := s[i : i+n]
+		if u, ok := cmap.mappings[cmapCode{cmapCodeOf(string(code)), n}]; ok {
+			out = append(out, u...)
+		} else {
+			out = append(out, fallback(code)...)
+		}
+		i += n
+	}
+	return string(out)
+}
+
+func (cmap *toUnicodeCMap) codeLength(s []byte) int {
+	if n, ok := codeLength(cmap.codespaces, s); ok {
+		return n
+	}
+	if len(cmap.codespaces) == 0 {
+		for n := 1; n <= 4 && n <= len(s); n++ {
+			if _, ok := cmap.mappings[cmapCode{cmapCodeOf(string(s[:n])), n}]; ok {
+				return n
+			}
+		}
+	}
+	return 1
+}
+
+// codeLength returns the length of the code at the start of s by the codespace ranges.
+// 9.7.6.2 CMap Mapping
+func codeLength(codespaces []codespaceRange, s []byte) (int, bool) {
+	for n := 1; n <= 4 && n <= len(s); n++ {
+		code := cmapCodeOf(string(s[:n]))
+		for _, r := range codespaces {
+			if r.n == n && r.low <= code && code <= r.high {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func cmapCodeOf(b string) uint32 {
+	var code uint32
+	for i := 0; i < len(b); i++ {
+		code = code<<8 | uint32(b[i])
+	}
+	return code
+}
+
+func utf16Units(b string) []uint16 {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return units
+}
+
+// utf16BEString decodes UTF-16BE without the byte order mark.
+// A single byte is read as Latin-1 which some writers use.
+func utf16BEString(b string) string {
+	if len(b) == 1 {
+		return string(rune(b[0]))
+	}
+	return string(utf16.Decode(utf16Units(b)))
+}
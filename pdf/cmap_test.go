@@ -0,0 +1,56 @@
+package pdf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseToUnicodeCMap(t *testing.T) {
+	cmap := []byte(`/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+2 beginbfchar
+<0003> <0020>
+<0011> <00E9>
+endbfchar
+2 beginbfrange
+<0024> <0026> <0041>
+<0030> <0032> [<03B1> <03B2> <03B3>]
+endbfrange
+endcmap
+end
+end`)
+
+	got, err := ParseToUnicodeCMap(cmap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[uint32]rune{
+		0x03: ' ',
+		0x11: 'é',
+		0x24: 'A', 0x25: 'B', 0x26: 'C',
+		0x30: 'α', 0x31: 'β', 0x32: 'γ',
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseToUnicodeCMapLastCode(t *testing.T) {
+	// the range ends at the largest code so that the code must not wrap around
+	for _, cmap := range []string{
+		"begincmap 1 beginbfrange <FFFFFFFF> <FFFFFFFF> <0041> endbfrange endcmap",
+		"begincmap 1 beginbfrange <FFFFFFFE> <FFFFFFFF> [<0041> <0042>] endbfrange endcmap",
+	} {
+		got, err := ParseToUnicodeCMap([]byte(cmap))
+		if err != nil {
+			t.Fatalf("%s: %v", cmap, err)
+		}
+		if got[0xFFFFFFFF] == 0 || len(got) > 2 {
+			t.Errorf("%s: got %v", cmap, got)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// 7.8.2 Content Streams
+// ContentOp is an operator with its operands.
+type ContentOp struct {
+	Operator string
+	Operands []PDFObject
+
+	// Data is the raw image data of an inline image (BI ... ID data EI).
+	// The operator is BI and the only operand is the image dictionary as written. See InlineImage.
+	Data []byte
+}
+
+// ParseContentStream parses a content stream into operators.
+// Operands are parsed as objects and any keyword other than true, false and null is an operator.
+func ParseContentStream(b []byte) ([]ContentOp, error) {
+	return ParseContentStreamContext(context.Background(), b)
+}
+
+// ParseContentStreamContext is the same as ParseContentStream but aborts parsing when ctx is done.
+func ParseContentStreamContext(ctx context.Context, b []byte) ([]ContentOp, error) {
+	var ops []ContentOp
+	var operands []PDFObject
+
+	// base is the offset in b where the lexer started
+	base := int64(0)
+	p := newObjectParser(NewLexer(bytes.NewReader(b)))
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok.Kind == TokenEOF {
+			break
+		}
+
+		if !isOperator(tok) {
+			obj, err := p.parseToken(tok)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse content stream: %w", err)
+			}
+			p.commit()
+			operands = append(operands, obj)
+			continue
+		}
+		p.commit()
+
+		if err := contextErr(ctx); err != nil {
+			return nil, err
+		}
+
+		if tok.Value != "BI" {
+			ops = append(ops, ContentOp{Operator: tok.Value, Operands: operands})
+			operands = nil
+			continue
+		}
+
+		// 8.9.7 Inline Images
+		dict, err := parseInlineImageDict(p)
+		if err != nil {
+			return nil, err
+		}
+
+		// a single white-space character follows ID
+		start := base + p.lex.Offset() + 1
+		end, next, err := findInlineImageEnd(b, start, ExpandInlineImageDict(dict))
+		if err != nil {
+			return nil, err
+		}
+
+		ops = append(ops, ContentOp{Operator: "BI", Operands: []PDFObject{dict}, Data: b[start:end]})
+		operands = nil
+
+		// continue after EI
+		base = next
+		p = newObjectParser(NewLexer(bytes.NewReader(b[next:])))
+	}
+
+	if len(operands) > 0 {
+		return nil, errors.New("content stream ends with operands without an operator")
+	}
+	return ops, nil
+}
+
+func isOperator(tok Token) bool {
+	if tok.Kind != TokenKeyword {
+		return false
+	}
+	switch tok.Value {
+	case "true", "false", "null":
+		return false
+	}
+	return true
+}
+
+// parseInlineImageDict parses key-value pairs up to ID.
+func parseInlineImageDict(p *objectParser) (PDFDict, error) {
+	dict := PDFDict{}
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case tok.Is(TokenKeyword, "ID"):
+			p.commit()
+			return dict, nil
+		case tok.Kind == TokenEOF:
+			return nil, errors.New("inline image must have ID")
+		case tok.Kind != TokenName:
+			return nil, fmt.Errorf("inline image key must be a name but got %s %q", tok.Kind, tok.Value)
+		}
+
+		vtok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.parseToken(vtok)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the value of /%s: %w", tok.Value, err)
+		}
+		p.commit()
+		dict[tok.Value] = value
+	}
+}
+
+// findInlineImageEnd returns the end of the image data starting at start and the offset after EI.
+// When the length of the data is known from dict, EI must follow the data after optional white-space.
+// Otherwise EI must be preceded by a white-space character and must be followed by a delimiter or the end.
+func findInlineImageEnd(b []byte, start int64, dict PDFDict) (int64, int64, error) {
+	if start > int64(len(b)) {
+		return 0, 0, errors.New("unexpected end of inline image")
+	}
+
+	if n, ok := inlineImageDataEnd(b[start:], dict); ok {
+		end := start + n
+		i := end
+		for i < int64(len(b)) && isWhitespace(b[i]) {
+			i++
+		}
+		if i+2 <= int64(len(b)) && b[i] == 'E' && b[i+1] == 'I' && (i+2 == int64(len(b)) || !isRegular(b[i+2])) {
+			return end, i + 2, nil
+		}
+		// the length is wrong so fall back to looking for EI
+	}
+
+	for i := start; i+2 <= int64(len(b)); i++ {
+		if b[i] != 'E' || b[i+1] != 'I' {
+			continue
+		}
+		if i > start && !isWhitespace(b[i-1]) {
+			continue
+		}
+		if i+2 < int64(len(b)) && isRegular(b[i+2]) {
+			continue
+		}
+
+		end := i
+		if end > start {
+			// the white-space before EI is not a part of the data
+			end--
+		}
+		return end, i + 2, nil
+	}
+	return 0, 0, errors.New("inline image must end with EI")
+}
@@ -0,0 +1,15 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+)
+
+// contextErr returns the error of ctx wrapped if ctx is done so that
+// the caller can tell cancellation from parse errors with errors.Is.
+func contextErr(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("aborted: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,481 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// ErrWrongPassword is returned when the password matches neither the user nor the owner password.
+var ErrWrongPassword = errors.New("wrong password")
+
+// ErrUnsupportedEncryption is returned when the security handler or the algorithm is not supported.
+var ErrUnsupportedEncryption = errors.New("unsupported encryption")
+
+// 7.6.4.3 Padding for passwords
+var passwordPadding = []byte{
+	0x28, 0xbf, 0x4e, 0x5e, 0x4e, 0x75, 0x8a, 0x41, 0x64, 0x00, 0x4e, 0x56, 0xff, 0xfa, 0x01, 0x08,
+	0x2e, 0x2e, 0x00, 0xb6, 0xd0, 0x68, 0x3e, 0x80, 0x2f, 0x0c, 0xa9, 0xfe, 0x64, 0x53, 0x69, 0x7a,
+}
+
+type securityHandler struct {
+	sec StandardSecurity
+	key []byte
+
+	// crypt filter methods (None, V2, AESV2 or AESV3) for streams and strings
+	stmMethod PDFName
+	strMethod PDFName
+
+	// the encryption dictionary itself is not encrypted
+	encryptRef PDFRef
+}
+
+// Decrypt authenticates password as the user or the owner password.
+// Once authenticated, strings and streams are decrypted transparently.
+// NewDocument tries the empty user password so this is needed only for protected documents.
+func (d *Document) Decrypt(password string) error {
+	encrypted, dict, err := d.IsEncrypted()
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		return nil
+	}
+
+	sec, err := ReadStandardSecurity(dict)
+	if err != nil {
+		return err
+	}
+
+	id, err := d.firstFileID()
+	if err != nil {
+		return err
+	}
+
+	key, err := sec.authenticate([]byte(password), id)
+	if err != nil {
+		return err
+	}
+
+	stmMethod, err := sec.cryptMethod(sec.StmF)
+	if err != nil {
+		return err
+	}
+	strMethod, err := sec.cryptMethod(sec.StrF)
+	if err != nil {
+		return err
+	}
+
+	encryptRef, _ := d.trailerDict["Encrypt"].(PDFRef)
+	d.crypt = &securityHandler{
+		sec:        sec,
+		key:        key,
+		stmMethod:  stmMethod,
+		strMethod:  strMethod,
+		encryptRef: encryptRef,
+	}
+
+	// objects read so far are not decrypted
+	d.cache = map[PDFRef]PDFObject{}
+	d.objStmCache = map[int64]*objectStream{}
+
+	return nil
+}
+
+func (d *Document) firstFileID() ([]byte, error) {
+	ids, ok := d.trailerDict["ID"].(PDFArray)
+	if !ok || len(ids) == 0 {
+		return nil, errors.New("encrypted document must have /ID")
+	}
+	id, ok := ids[0].(PDFString)
+	if !ok {
+		return nil, errors.New("/ID must be an array of strings")
+	}
+	return []byte(id), nil
+}
+
+// cryptMethod returns the method of the crypt filter name.
+// 7.6.5 Crypt Filters
+func (sec StandardSecurity) cryptMethod(name PDFName) (PDFName, error) {
+	if sec.V < 4 {
+		return "V2", nil
+	}
+	if name == "Identity" {
+		return "None", nil
+	}
+
+	filter, ok := sec.CF[name]
+	if !ok {
+		return "", fmt.Errorf("crypt filter /%s is not defined", name)
+	}
+	switch filter.CFM {
+	case "None", "V2", "AESV2", "AESV3":
+		return filter.CFM, nil
+	}
+	return "", fmt.Errorf("%w: crypt filter method /%s", ErrUnsupportedEncryption, filter.CFM)
+}
+
+// authenticate returns the file encryption key if password is the user or the owner password.
+func (sec StandardSecurity) authenticate(password, id []byte) ([]byte, error) {
+	switch {
+	case (sec.V == 1 || sec.V == 2) && (sec.R == 2 || sec.R == 3):
+	case sec.V == 4 && sec.R == 4:
+	case sec.V == 5 && (sec.R == 5 || sec.R == 6):
+		return sec.authenticateAES256(password)
+	case sec.V != 1 && sec.V != 2 && sec.V != 4 && sec.V != 5:
+		return nil, fmt.Errorf("%w: algorithm /V %d", ErrUnsupportedEncryption, sec.V)
+	default:
+		return nil, fmt.Errorf("%w: standard security handler revision /R %d", ErrUnsupportedEncryption, sec.R)
+	}
+
+	if key := sec.userKey(password, id); key != nil {
+		return key, nil
+	}
+
+	// Algorithm 7: the owner password decrypts /O into the user password
+	ownerKey := sec.ownerKey(password)
+	user := append([]byte(nil), sec.O...)
+	if sec.R == 2 {
+		user = rc4XOR(ownerKey, user)
+	} else {
+		for i := 19; i >= 0; i-- {
+			user = rc4XOR(xorKey(ownerKey, byte(i)), user)
+		}
+	}
+	if key := sec.userKey(user, id); key != nil {
+		return key, nil
+	}
+
+	return nil, ErrWrongPassword
+}
+
+func (sec StandardSecurity) keyLength() int {
+	if sec.R == 2 {
+		return 5
+	}
+	if sec.V == 4 {
+		if filter, ok := sec.CF[sec.StmF]; ok && filter.Length >= 5 && filter.Length <= 16 {
+			return filter.Length
+		}
+		return 16
+	}
+	return sec.Length / 8
+}
+
+// userKey returns the file encryption key if password is the user password.
+// Algorithm 4 and 5
+func (sec StandardSecurity) userKey(password, id []byte) []byte {
+	key := sec.fileKey(password, id)
+
+	if sec.R == 2 {
+		if bytes.Equal(rc4XOR(key, passwordPadding), sec.U) {
+			return key
+		}
+		return nil
+	}
+
+	h := md5.New()
+	h.Write(passwordPadding)
+	h.Write(id)
+	u := h.Sum(nil)
+	for i := 0; i < 20; i++ {
+		u = rc4XOR(xorKey(key, byte(i)), u)
+	}
+	if len(sec.U) >= 16 && bytes.Equal(u, sec.U[:16]) {
+		return key
+	}
+	return nil
+}
+
+// Algorithm 2: Computing a file encryption key
+func (sec StandardSecurity) fileKey(password, id []byte) []byte {
+	n := sec.keyLength()
+
+	h := md5.New()
+	h.Write(padPassword(password))
+	h.Write(sec.O)
+	p := uint32(sec.P)
+	h.Write([]byte{byte(p), byte(p >> 8), byte(p >> 16), byte(p >> 24)})
+	h.Write(id)
+	if sec.R >= 4 && !sec.EncryptMetadata {
+		h.Write([]byte{0xff, 0xff, 0xff, 0xff})
+	}
+	sum := h.Sum(nil)
+
+	if sec.R >= 3 {
+		for i := 0; i < 50; i++ {
+			s := md5.Sum(sum[:n])
+			sum = s[:]
+		}
+	}
+
+	return sum[:n]
+}
+
+// ownerKey returns the key to decrypt /O. Algorithm 3 step (a) to (d)
+func (sec StandardSecurity) ownerKey(password []byte) []byte {
+	n := sec.keyLength()
+
+	s := md5.Sum(padPassword(password))
+	sum := s[:]
+	if sec.R >= 3 {
+		for i := 0; i < 50; i++ {
+			s := md5.Sum(sum)
+			sum = s[:]
+		}
+	}
+	return sum[:n]
+}
+
+// authenticateAES256 returns the file encryption key for R 5 and 6.
+// Algorithm 2.A: Retrieving the file encryption key from an encrypted document
+func (sec StandardSecurity) authenticateAES256(password []byte) ([]byte, error) {
+	// the password is truncated to 127 bytes in UTF-8
+	if len(password) > 127 {
+		password = password[:127]
+	}
+	if len(sec.U) < 48 || len(sec.O) < 48 || len(sec.UE) < 32 || len(sec.OE) < 32 {
+		return nil, errors.New("/O, /U, /OE or /UE is too short")
+	}
+
+	// owner password: validation salt and key salt follow the 32-byte hash
+	if bytes.Equal(sec.hash(password, sec.O[32:40], sec.U[:48]), sec.O[:32]) {
+		return aesCBCNoPadding(sec.hash(password, sec.O[40:48], sec.U[:48]), sec.OE[:32])
+	}
+
+	// user password
+	if bytes.Equal(sec.hash(password, sec.U[32:40], nil), sec.U[:32]) {
+		return aesCBCNoPadding(sec.hash(password, sec.U[40:48], nil), sec.UE[:32])
+	}
+
+	return nil, ErrWrongPassword
+}
+
+// hash computes the hash of password for R 5 and 6.
+// Algorithm 2.B: Computing a hash (revision 6 and later)
+func (sec StandardSecurity) hash(password, salt, udata []byte) []byte {
+	h := sha256.New()
+	h.Write(password)
+	h.Write(salt)
+	h.Write(udata)
+	k := h.Sum(nil)
+
+	if sec.R == 5 {
+		return k
+	}
+
+	for i := 0; ; i++ {
+		k1 := make([]byte, 0, 64*(len(password)+len(k)+len(udata)))
+		for j := 0; j < 64; j++ {
+			k1 = append(k1, password...)
+			k1 = append(k1, k...)
+			k1 = append(k1, udata...)
+		}
+
+		block, err := aes.NewCipher(k[:16])
+		if err != nil {
+			// the key is always 16 bytes here
+			panic(err)
+		}
+		e := make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(e, k1)
+
+		// the first 16 bytes of e as a big-endian number modulo 3 chooses the hash function
+		var sum int
+		for _, b := range e[:16] {
+			sum += int(b)
+		}
+		var next hash.Hash
+		switch sum % 3 {
+		case 0:
+			next = sha256.New()
+		case 1:
+			next = sha512.New384()
+		case 2:
+			next = sha512.New()
+		}
+		next.Write(e)
+		k = next.Sum(nil)
+
+		if i >= 63 && int(e[len(e)-1]) <= i-31 {
+			break
+		}
+	}
+
+	return k[:32]
+}
+
+// aesCBCNoPadding decrypts b with AES-256 in CBC mode with a zero IV and no padding.
+func aesCBCNoPadding(key, b []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(b))
+	cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(out, b)
+	return out, nil
+}
+
+func padPassword(password []byte) []byte {
+	padded := make([]byte, 0, 32)
+	if len(password) > 32 {
+		password = password[:32]
+	}
+	padded = append(padded, password...)
+	return append(padded, passwordPadding[:32-len(password)]...)
+}
+
+func xorKey(key []byte, x byte) []byte {
+	k := make([]byte, len(key))
+	for i := range key {
+		k[i] = key[i] ^ x
+	}
+	return k
+}
+
+func rc4XOR(key, b []byte) []byte {
+	c, err := rc4.NewCipher(key)
+	if err != nil {
+		// the key length is always between 1 and 256 bytes here
+		panic(err)
+	}
+	out := make([]byte, len(b))
+	c.XORKeyStream(out, b)
+	return out
+}
+
+// Algorithm 1: Encryption of data using the RC4 or AES algorithms
+func (h *securityHandler) objectKey(ref PDFRef, aes bool) []byte {
+	n := uint32(ref.Number)
+	g := uint32(ref.Generation)
+
+	md := md5.New()
+	md.Write(h.key)
+	md.Write([]byte{byte(n), byte(n >> 8), byte(n >> 16), byte(g), byte(g >> 8)})
+	if aes {
+		md.Write([]byte("sAlT"))
+	}
+	sum := md.Sum(nil)
+
+	keyLen := len(h.key) + 5
+	if keyLen > 16 {
+		keyLen = 16
+	}
+	return sum[:keyLen]
+}
+
+// decryptBytes decrypts b in the indirect object ref with the crypt filter method.
+func (h *securityHandler) decryptBytes(method PDFName, ref PDFRef, b []byte) ([]byte, error) {
+	switch method {
+	case "None":
+		return b, nil
+	case "V2":
+		return rc4XOR(h.objectKey(ref, false), b), nil
+	case "AESV2":
+		return aesCBCDecrypt(h.objectKey(ref, true), b)
+	case "AESV3":
+		return aesCBCDecrypt(h.key, b)
+	}
+	return nil, fmt.Errorf("%w: crypt filter method /%s", ErrUnsupportedEncryption, method)
+}
+
+// aesCBCDecrypt decrypts b whose first 16 bytes are the IV and removes the PKCS#5 padding.
+// 7.6.3.2 Algorithm 1.A
+func aesCBCDecrypt(key, b []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) == 0 {
+		return b, nil
+	}
+	if len(b) < 2*aes.BlockSize || len(b)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid length of AES encrypted data: %d", len(b))
+	}
+
+	iv, data := b[:aes.BlockSize], b[aes.BlockSize:]
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+
+	pad := int(out[len(out)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return nil, errors.New("invalid AES padding")
+	}
+	for _, c := range out[len(out)-pad:] {
+		if int(c) != pad {
+			return nil, errors.New("invalid AES padding")
+		}
+	}
+	return out[:len(out)-pad], nil
+}
+
+// streamMethod returns the crypt filter method for the stream.
+// 7.4.10 Crypt Filter: a stream may override /StmF with the Crypt filter.
+func (h *securityHandler) streamMethod(dict PDFDict) PDFName {
+	filters, parms, err := streamFilters(dict)
+	if err != nil || len(filters) == 0 || filters[0] != "Crypt" {
+		return h.stmMethod
+	}
+
+	name := PDFName("Identity")
+	if n, ok := parms[0]["Name"].(PDFName); ok {
+		name = n
+	}
+	method, err := h.sec.cryptMethod(name)
+	if err != nil {
+		return h.stmMethod
+	}
+	return method
+}
+
+// decryptObject decrypts strings in obj which is the indirect object ref.
+func (h *securityHandler) decryptObject(ref PDFRef, obj PDFObject) (PDFObject, error) {
+	if ref == h.encryptRef {
+		return obj, nil
+	}
+
+	switch obj := obj.(type) {
+	case PDFString:
+		b, err := h.decryptBytes(h.strMethod, ref, obj)
+		if err != nil {
+			return nil, err
+		}
+		return PDFString(b), nil
+	case PDFArray:
+		arr := make(PDFArray, len(obj))
+		for i := range obj {
+			v, err := h.decryptObject(ref, obj[i])
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = v
+		}
+		return arr, nil
+	case PDFDict:
+		dict := make(PDFDict, len(obj))
+		for k, v := range obj {
+			dv, err := h.decryptObject(ref, v)
+			if err != nil {
+				return nil, err
+			}
+			dict[k] = dv
+		}
+		return dict, nil
+	case PDFStream:
+		dict, err := h.decryptObject(ref, obj.Dict)
+		if err != nil {
+			return nil, err
+		}
+		return PDFStream{Dict: dict.(PDFDict)}, nil
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,82 @@
+package pdf
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecryptBlankPassword(t *testing.T) {
+	for _, tc := range []struct {
+		file   string
+		v, r   int
+		method PDFName
+	}{
+		{file: "rc4-blank-password.pdf", v: 2, r: 3, method: "V2"},
+		{file: "aes128-blank-password.pdf", v: 4, r: 4, method: "AESV2"},
+		{file: "aes256-blank-password.pdf", v: 5, r: 6, method: "AESV3"},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			path := filepath.Join("testdata", tc.file)
+
+			// the user password is blank and the owner password is "owner"
+			for _, password := range []string{"", "owner"} {
+				d, err := OpenFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				encrypted, dict, err := d.IsEncrypted()
+				if err != nil || !encrypted {
+					t.Fatalf("got %v, %v, want encrypted", encrypted, err)
+				}
+				sec, err := ReadStandardSecurity(dict)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if sec.V != tc.v || sec.R != tc.r {
+					t.Errorf("got /V %d /R %d, want /V %d /R %d", sec.V, sec.R, tc.v, tc.r)
+				}
+
+				if err := d.Decrypt(password); err != nil {
+					t.Fatalf("password %q: %v", password, err)
+				}
+				if d.crypt.strMethod != tc.method || d.crypt.stmMethod != tc.method {
+					t.Errorf("got %s and %s, want %s", d.crypt.strMethod, d.crypt.stmMethod, tc.method)
+				}
+
+				// a string
+				info, err := d.Info()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if info.Title != "Encrypted title" {
+					t.Errorf("password %q: got the title %q", password, info.Title)
+				}
+
+				// a stream
+				pages, err := d.Pages()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(pages) == 0 {
+					t.Fatal("no page")
+				}
+				text, err := d.PageText(pages[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(text, "Secret text") {
+					t.Errorf("password %q: got the text %q", password, text)
+				}
+			}
+
+			d, err := OpenFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := d.Decrypt("wrong"); err == nil {
+				t.Error("a wrong password is accepted")
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ResolveDest returns the zero-based page index and the view parameters (e.g. /XYZ left top zoom) of dest.
+// dest is an explicit destination array, a named destination as a name or a string,
+// or a dictionary with /D such as a GoTo action.
+// 12.3.2 Destinations
+func (d *Document) ResolveDest(dest PDFObject) (int, []PDFObject, error) {
+	// follow named destinations and dictionaries a limited number of times to avoid loops
+	for i := 0; i < 8; i++ {
+		resolved, err := d.Resolve(dest)
+		if err != nil {
+			return 0, nil, fmt.Errorf("unable to resolve the destination: %w", err)
+		}
+
+		switch obj := resolved.(type) {
+		case PDFArray:
+			return d.explicitDest(obj)
+		case PDFDict:
+			next, ok := obj["D"]
+			if !ok {
+				return 0, nil, errors.New("destination dictionary must have /D")
+			}
+			dest = next
+		case PDFName:
+			dest, err = d.namedDest(string(obj))
+			if err != nil {
+				return 0, nil, err
+			}
+		case PDFString:
+			dest, err = d.namedDest(string(obj))
+			if err != nil {
+				return 0, nil, err
+			}
+		default:
+			return 0, nil, fmt.Errorf("unexpected destination %T", resolved)
+		}
+	}
+	return 0, nil, errors.New("too many indirections in the destination")
+}
+
+// 12.3.2.2 Explicit Destinations
+func (d *Document) explicitDest(dest PDFArray) (int, []PDFObject, error) {
+	if len(dest) == 0 {
+		return 0, nil, errors.New("destination must not be empty")
+	}
+	view := []PDFObject(dest[1:])
+
+	switch page := dest[0].(type) {
+	case PDFInt:
+		// the page number in a remote go-to action
+		return int(page), view, nil
+	case PDFRef:
+		refs, err := d.pageRefs()
+		if err != nil {
+			return 0, nil, err
+		}
+		for i, ref := range refs {
+			if ref == page {
+				return i, view, nil
+			}
+		}
+		return 0, nil, fmt.Errorf("%d %d R is not a page", page.Number, page.Generation)
+	}
+	return 0, nil, fmt.Errorf("destination page must be a reference or an integer but got %T", dest[0])
+}
+
+// 12.3.2.3 Named Destinations
+// The name is looked up in /Dests in the name dictionary (PDF 1.2) and then in /Dests in the catalog (PDF 1.1).
+func (d *Document) namedDest(name string) (PDFObject, error) {
+	catalog, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := d.resolveDict(catalog["Names"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /Names: %w", err)
+	}
+	if _, ok := names["Dests"]; ok {
+		root, err := d.resolveDict(names["Dests"])
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve /Dests: %w", err)
+		}
+		dest, err := d.LookupName(root, name)
+		if err != nil {
+			return nil, err
+		}
+		if dest != nil {
+			return dest, nil
+		}
+	}
+
+	dests, err := d.resolveDict(catalog["Dests"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /Dests: %w", err)
+	}
+	if dest, ok := dests[name]; ok {
+		return dest, nil
+	}
+
+	return nil, fmt.Errorf("named destination %q is not found", name)
+}
@@ -0,0 +1,481 @@
+// Package pdf reads the structure and objects of PDF files.
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// DefaultMaxDepth is the default limit of the depth of reference chains and trees.
+const DefaultMaxDepth = 1000
+
+// ErrMaxDepthExceeded is returned when a reference chain or a tree is deeper than the limit.
+// Cycles are reported separately but a deep acyclic structure can still exhaust the stack.
+var ErrMaxDepthExceeded = errors.New("maximum depth exceeded")
+
+// ErrNoEntry is returned when the cross-reference table has no entry for the object.
+var ErrNoEntry = errors.New("no entry found")
+
+// Document provides access to objects in a PDF file.
+type Document struct {
+	ra      io.ReaderAt
+	trailer Trailer
+	entries []XrefEntry
+	// entryIndex is built lazily from entries and keyed by the object number and the generation
+	entryIndex map[[2]int64]XrefEntry
+
+	// the trailer dictionary of the newest revision
+	trailerDict PDFDict
+
+	cache       map[PDFRef]PDFObject
+	objStmCache map[int64]*objectStream
+	// objStmLoading has the object streams being decoded by objectStream
+	objStmLoading map[int64]bool
+
+	// crypt is set once the document is decrypted
+	crypt *securityHandler
+
+	// closer closes the file when the document opened it
+	closer io.Closer
+	// openStreams are readers returned by OpenStream and not closed yet
+	openStreams map[*streamReader]struct{}
+
+	// MaxDecodedSize limits the size of a decoded stream in bytes.
+	// 0 means DefaultMaxDecodedSize and a negative value means no limit.
+	MaxDecodedSize int64
+
+	// MaxDepth limits the depth of reference chains and the page, outline, name and number trees.
+	// 0 means DefaultMaxDepth and a negative value means no limit.
+	MaxDepth int
+}
+
+// Open opens the PDF file of size bytes read from ra.
+func Open(ra io.ReaderAt, size int64) (*Document, error) {
+	return NewDocument(ra, size)
+}
+
+// OpenFile opens the PDF file at path. The file is closed by Close.
+func OpenFile(path string) (*Document, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	fstat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	d, err := NewDocument(f, fstat.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	d.closer = f
+	return d, nil
+}
+
+// OpenReader opens the PDF file read from r until EOF.
+// r may be a pipe which cannot seek. The whole file is held in memory so that Seekable reports false.
+func OpenReader(r io.Reader) (*Document, error) {
+	ra, size, err := BufferInput(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewDocument(ra, size)
+}
+
+// bufferedInput is the whole input read into memory by BufferInput.
+type bufferedInput struct {
+	*bytes.Reader
+}
+
+// BufferInput reads r until EOF into memory and returns it with the size for NewDocument and the other constructors.
+// A document made from it reports false for Seekable.
+func BufferInput(r io.Reader) (io.ReaderAt, int64, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to read input: %w", err)
+	}
+	return bufferedInput{bytes.NewReader(b)}, int64(len(b)), nil
+}
+
+// Seekable reports whether the document reads parts of the source on demand, which is the case
+// for a file or any io.ReaderAt given by the caller. A document whose source is buffered by OpenReader
+// or BufferInput has loaded the whole file, so partial loading such as the linearized first-page
+// fast path gains nothing.
+func (d *Document) Seekable() bool {
+	_, buffered := d.ra.(bufferedInput)
+	return !buffered
+}
+
+// Close closes readers returned by OpenStream and not closed yet, and the file if the document was opened by OpenFile.
+// A document made from an io.ReaderAt given by the caller does not close it; the caller closes it after Close.
+// The document must not be used after Close.
+func (d *Document) Close() error {
+	var err error
+	for sr := range d.openStreams {
+		if cerr := sr.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	d.cache = map[PDFRef]PDFObject{}
+	d.objStmCache = map[int64]*objectStream{}
+
+	if d.closer != nil {
+		if cerr := d.closer.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		d.closer = nil
+	}
+	return err
+}
+
+// OpenContext is the same as Open but aborts reading cross-reference sections when ctx is done.
+func OpenContext(ctx context.Context, ra io.ReaderAt, size int64) (*Document, error) {
+	return newDocument(ctx, ra, size, false)
+}
+
+func NewDocument(ra io.ReaderAt, size int64) (*Document, error) {
+	return newDocument(context.Background(), ra, size, false)
+}
+
+// NewStrictDocument is the same as NewDocument but fails on a cross-reference table entry
+// which is not exactly 20 bytes long instead of recovering from it.
+func NewStrictDocument(ra io.ReaderAt, size int64) (*Document, error) {
+	return newDocument(context.Background(), ra, size, true)
+}
+
+func newDocument(ctx context.Context, ra io.ReaderAt, size int64, strict bool) (*Document, error) {
+	tr, err := ReadTrailer(ra, size)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the trailer: %w", err)
+	}
+	tr.Strict = strict
+
+	entries, trailerDict, err := tr.resolveAllEntries(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		// offsets may be relative to the header when there are bytes before %PDF-
+		base, _, herr := readHeader(ra)
+		if herr != nil || base == 0 {
+			return nil, fmt.Errorf("unable to read xref entries: %w", err)
+		}
+		shifted := tr
+		shifted.StartXref += base
+		shifted.base = base
+
+		entries, trailerDict, err = shifted.resolveAllEntries(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read xref entries: %w", err)
+		}
+		tr = shifted
+	}
+
+	d := &Document{
+		ra:          ra,
+		trailer:     tr,
+		entries:     entries,
+		trailerDict: trailerDict,
+		cache:       map[PDFRef]PDFObject{},
+		objStmCache: map[int64]*objectStream{},
+	}
+
+	// most encrypted documents have the empty user password.
+	// The document is left locked if it does not work.
+	d.Decrypt("")
+
+	return d, nil
+}
+
+func (d *Document) decodedSizeLimit() int64 {
+	if d.MaxDecodedSize == 0 {
+		return DefaultMaxDecodedSize
+	}
+	return d.MaxDecodedSize
+}
+
+func (d *Document) maxDepth() int {
+	if d.MaxDepth == 0 {
+		return DefaultMaxDepth
+	}
+	return d.MaxDepth
+}
+
+// checkDepth returns ErrMaxDepthExceeded naming obj when depth is over the limit.
+func (d *Document) checkDepth(depth int, obj PDFObject) error {
+	if limit := d.maxDepth(); limit < 0 || depth <= limit {
+		return nil
+	}
+	if ref, ok := obj.(PDFRef); ok {
+		return fmt.Errorf("%w at %d %d R", ErrMaxDepthExceeded, ref.Number, ref.Generation)
+	}
+	return fmt.Errorf("%w at a direct object", ErrMaxDepthExceeded)
+}
+
+// HeaderOffset returns the number of bytes before the %PDF- header
+// when offsets in the file are relative to the header. It is 0 otherwise.
+func (d *Document) HeaderOffset() int64 {
+	return d.trailer.base
+}
+
+// TrailerDict returns the trailer dictionary of the newest revision.
+// It is the dictionary of the cross-reference stream in a file with cross-reference streams.
+func (d *Document) TrailerDict() PDFDict {
+	return d.trailerDict
+}
+
+// XrefEntries returns all cross-reference entries sorted by the object number.
+func (d *Document) XrefEntries() []XrefEntry {
+	return d.entries
+}
+
+// FreeList returns the entries of free objects including the head of the free list (object 0).
+// 7.5.4 The generation of a free entry is the one used when the object number is reused.
+func (d *Document) FreeList() []XrefEntry {
+	var free []XrefEntry
+	for _, ent := range d.entries {
+		if !ent.InUse {
+			free = append(free, ent)
+		}
+	}
+	return free
+}
+
+// XrefEntry returns the cross-reference entry of the object.
+func (d *Document) XrefEntry(number int64, generation int) (XrefEntry, error) {
+	if d.entryIndex == nil {
+		// entries have only the newest entry for each object so there is no conflict
+		d.entryIndex = make(map[[2]int64]XrefEntry, len(d.entries))
+		for _, ent := range d.entries {
+			d.entryIndex[[2]int64{ent.Number, int64(ent.Generation)}] = ent
+		}
+	}
+
+	ent, ok := d.entryIndex[[2]int64{number, int64(generation)}]
+	if !ok {
+		// only the newest entry of each object number is kept so there is one generation to report
+		if other, ok := d.entryOf(number); ok {
+			state := "exists"
+			if !other.InUse {
+				state = "is free"
+			}
+			return XrefEntry{}, fmt.Errorf("%w for generation %d but object %d %s with generation %d", ErrNoEntry, generation, number, state, other.Generation)
+		}
+		return XrefEntry{}, ErrNoEntry
+	}
+	return ent, nil
+}
+
+// entryOf returns the entry of the object number with any generation.
+func (d *Document) entryOf(number int64) (XrefEntry, bool) {
+	// entries are sorted by the object number
+	i := sort.Search(len(d.entries), func(i int) bool { return d.entries[i].Number >= number })
+	if i < len(d.entries) && d.entries[i].Number == number {
+		return d.entries[i], true
+	}
+	return XrefEntry{}, false
+}
+
+// ReadEntry returns the raw bytes of the object at ent from "N G obj" to "endobj".
+func (d *Document) ReadEntry(ent XrefEntry) ([]byte, error) {
+	return readEntry(ent, d.ra)
+}
+
+// RawObject returns the bytes of the object of number and generation from "N G obj" through "endobj" as they are in the file.
+// The body of a stream is spanned with /Length so that it can contain endobj.
+// An object in an object stream has no bytes of its own and an error is returned.
+func (d *Document) RawObject(number int64, generation int) ([]byte, error) {
+	ent, err := d.XrefEntry(number, generation)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find %d %d R: %w", number, generation, err)
+	}
+	if !ent.InUse {
+		return nil, fmt.Errorf("%d %d R is free", number, generation)
+	}
+	if ent.Compressed {
+		return nil, fmt.Errorf("%d %d R is in the object stream %d", number, generation, ent.StreamNumber)
+	}
+
+	end, err := entryEnd(d.ra, ent.ByteOffset, d.Resolve)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %d %d R: %w", number, generation, err)
+	}
+
+	b := make([]byte, end-ent.ByteOffset)
+	if _, err := d.ra.ReadAt(b, ent.ByteOffset); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("unable to read %d %d R: %w", number, generation, err)
+	}
+	return b, nil
+}
+
+// GetObject returns the indirect object of number and generation.
+// Objects in object streams are read from the object stream.
+// A free or undefined object is PDFNull. An error is returned when the generation does not match the entry
+// of an object in use, and also for an undefined object of a document opened by NewStrictDocument.
+func (d *Document) GetObject(number int64, generation int) (PDFObject, error) {
+	return d.readObject(PDFRef{Number: number, Generation: generation})
+}
+
+// GetDict is the same as GetObject but the object must be a dictionary.
+func (d *Document) GetDict(number int64, generation int) (PDFDict, error) {
+	obj, err := d.GetObject(number, generation)
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := obj.(PDFDict)
+	if !ok {
+		return nil, fmt.Errorf("%d %d R must be a dictionary but got %T", number, generation, obj)
+	}
+	return dict, nil
+}
+
+// Resolve returns the object referenced by obj if obj is a reference.
+// A reference to another reference is followed until a direct object is found.
+// Other objects are returned as-is.
+func (d *Document) Resolve(obj PDFObject) (PDFObject, error) {
+	return d.ResolveContext(context.Background(), obj)
+}
+
+// ResolveContext is the same as Resolve but aborts following references when ctx is done.
+func (d *Document) ResolveContext(ctx context.Context, obj PDFObject) (PDFObject, error) {
+	visited := map[PDFRef]bool{}
+	for depth := 1; ; depth++ {
+		if err := contextErr(ctx); err != nil {
+			return nil, err
+		}
+
+		ref, ok := obj.(PDFRef)
+		if !ok {
+			return obj, nil
+		}
+		if err := d.checkDepth(depth, ref); err != nil {
+			return nil, err
+		}
+
+		if visited[ref] {
+			return nil, fmt.Errorf("cyclic reference at %d %d R", ref.Number, ref.Generation)
+		}
+		visited[ref] = true
+
+		resolved, err := d.readObject(ref)
+		if err != nil {
+			return nil, err
+		}
+		obj = resolved
+	}
+}
+
+func (d *Document) readObject(ref PDFRef) (PDFObject, error) {
+	if obj, ok := d.cache[ref]; ok {
+		return obj, nil
+	}
+
+	ent, err := d.XrefEntry(ref.Number, ref.Generation)
+	if err != nil {
+		// 7.3.10 a reference to an undefined object is the null object. It is an object number
+		// without an entry or a freed one whose entry has the generation for the next use.
+		if other, ok := d.entryOf(ref.Number); !d.trailer.Strict && (!ok || !other.InUse) {
+			return PDFNull{}, nil
+		}
+		return nil, fmt.Errorf("unable to find %d %d R: %w", ref.Number, ref.Generation, err)
+	}
+
+	// 7.3.10 a reference to a free object is the null object
+	if !ent.InUse {
+		return PDFNull{}, nil
+	}
+
+	obj, err := d.loadObject(ref, ent)
+	if err != nil {
+		return nil, err
+	}
+	d.cache[ref] = obj
+	return obj, nil
+}
+
+// loadObject reads the in-use object at ent without the cache.
+func (d *Document) loadObject(ref PDFRef, ent XrefEntry) (PDFObject, error) {
+	if ent.Compressed {
+		obj, err := d.readCompressedObject(ent.StreamNumber, ent.StreamIndex)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %d %d R: %w", ref.Number, ref.Generation, err)
+		}
+		return obj, nil
+	}
+
+	b, err := readEntry(ent, d.ra)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %d %d R: %w", ref.Number, ref.Generation, err)
+	}
+
+	obj, err := ParseObject(b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %d %d R: %w", ref.Number, ref.Generation, err)
+	}
+
+	if d.crypt != nil {
+		obj, err = d.crypt.decryptObject(ref, obj)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt %d %d R: %w", ref.Number, ref.Generation, err)
+		}
+	}
+	return obj, nil
+}
+
+// Objects calls fn for each in-use object including compressed ones in the order of the object number.
+// Objects which are not cached yet are read one at a time and are not added to the cache
+// so that memory does not grow with the number of objects. Decoded object streams are cached.
+// Iteration stops at the first error returned by fn or in reading an object.
+func (d *Document) Objects(fn func(number int64, generation int, obj PDFObject) error) error {
+	for _, ent := range d.entries {
+		if !ent.InUse {
+			continue
+		}
+		ref := PDFRef{Number: ent.Number, Generation: ent.Generation}
+
+		obj, ok := d.cache[ref]
+		if !ok {
+			var err error
+			obj, err = d.loadObject(ref, ent)
+			if err != nil {
+				return err
+			}
+		}
+		if err := fn(ent.Number, ent.Generation, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Catalog returns the document catalog. It is resolved as any object so that it may be in an object stream.
+// 7.7.2 Document Catalog
+func (d *Document) Catalog() (PDFDict, error) {
+	root, ok := d.trailerDict["Root"].(PDFRef)
+	if !ok {
+		return nil, errors.New("trailer must have /Root as an indirect reference")
+	}
+
+	obj, err := d.Resolve(root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /Root: %w", err)
+	}
+
+	catalog, ok := obj.(PDFDict)
+	if !ok {
+		return nil, fmt.Errorf("/Root must be a dictionary but got %T", obj)
+	}
+	if typ, _ := catalog["Type"].(PDFName); typ != "Catalog" {
+		return nil, fmt.Errorf("/Root must have /Type /Catalog but got %q", typ)
+	}
+
+	return catalog, nil
+}
@@ -0,0 +1,89 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// BenchmarkXrefEntry looks up every object of a file with 50k objects by the index
+// against a linear scan of the entries which the index replaced.
+func BenchmarkXrefEntry(b *testing.B) {
+	const n = 50000
+	d := openBytes(b, buildClassicPDF(n))
+
+	b.Run("index", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			number := int64(i%n + 1)
+			if _, err := d.XrefEntry(number, 0); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("scan", func(b *testing.B) {
+		entries := d.XrefEntries()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			number := int64(i%n + 1)
+			found := false
+			for _, ent := range entries {
+				if ent.Number == number && ent.Generation == 0 {
+					found = true
+					break
+				}
+			}
+			if !found {
+				b.Fatalf("%d 0 R is not found", number)
+			}
+		}
+	})
+}
+
+func TestGetObjectUndefined(t *testing.T) {
+	// 2 0 R is freed by the update and the free entry has generation 1
+	var updated bytes.Buffer
+	if err := openBytes(t, buildClassicPDF(3)).AppendUpdate(&updated, map[int64]PDFObject{2: nil}); err != nil {
+		t.Fatal(err)
+	}
+	b := updated.Bytes()
+
+	d := openBytes(t, b)
+	for _, ref := range []PDFRef{
+		{Number: 2, Generation: 0},
+		{Number: 2, Generation: 1},
+		{Number: 9, Generation: 0},
+	} {
+		obj, err := d.GetObject(ref.Number, ref.Generation)
+		if err != nil || obj != (PDFNull{}) {
+			t.Errorf("%v: got %v, %v, want null", ref, obj, err)
+		}
+		if obj, err := d.Resolve(ref); err != nil || obj != (PDFNull{}) {
+			t.Errorf("Resolve(%v): got %v, %v, want null", ref, obj, err)
+		}
+	}
+
+	// the generation of an object in use must match
+	_, err := d.GetObject(3, 1)
+	if !errors.Is(err, ErrNoEntry) || !strings.Contains(err.Error(), "object 3 exists with generation 0") {
+		t.Errorf("got %v", err)
+	}
+
+	strict, err := NewStrictDocument(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ref := range []PDFRef{{Number: 2, Generation: 0}, {Number: 9, Generation: 0}} {
+		if _, err := strict.GetObject(ref.Number, ref.Generation); !errors.Is(err, ErrNoEntry) {
+			t.Errorf("strict %v: got %v, want ErrNoEntry", ref, err)
+		}
+	}
+	if _, err := strict.GetObject(2, 0); err == nil || !strings.Contains(err.Error(), "object 2 is free with generation 1") {
+		t.Errorf("strict 2 0 R: got %v", err)
+	}
+	// a free entry of the matching generation is null even in the strict mode
+	if obj, err := strict.GetObject(2, 1); err != nil || obj != (PDFNull{}) {
+		t.Errorf("strict 2 1 R: got %v, %v, want null", obj, err)
+	}
+}
@@ -0,0 +1,112 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// 7.11.4 Embedded File Streams
+type EmbeddedFile struct {
+	// Name is the key in the EmbeddedFiles name tree
+	Name string
+	// FileName is the file name in the file specification
+	FileName string
+	// Size is the uncompressed size in /Params. It is -1 when unknown.
+	Size int64
+
+	d   *Document
+	ref PDFRef
+}
+
+// Open returns a reader over the decoded content of the embedded file.
+func (f EmbeddedFile) Open() (io.ReadCloser, error) {
+	ent, err := f.d.XrefEntry(f.ref.Number, f.ref.Generation)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find %d %d R: %w", f.ref.Number, f.ref.Generation, err)
+	}
+	return f.d.OpenStream(ent)
+}
+
+// EmbeddedFiles returns files in the EmbeddedFiles name tree in the name dictionary.
+func (d *Document) EmbeddedFiles() ([]EmbeddedFile, error) {
+	catalog, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	// 7.7.4 Name Dictionary
+	names, err := d.resolveDict(catalog["Names"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /Names: %w", err)
+	}
+	if _, ok := names["EmbeddedFiles"]; !ok {
+		return nil, nil
+	}
+	root, err := d.resolveDict(names["EmbeddedFiles"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /EmbeddedFiles: %w", err)
+	}
+
+	var files []EmbeddedFile
+	err = d.WalkNameTree(root, func(key string, val PDFObject) error {
+		file, err := d.embeddedFile(key, val)
+		if err != nil {
+			return fmt.Errorf("unable to read embedded file %q: %w", key, err)
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// 7.11.3 File Specification Dictionaries
+func (d *Document) embeddedFile(name string, obj PDFObject) (EmbeddedFile, error) {
+	file := EmbeddedFile{Name: DecodeTextString([]byte(name)), Size: -1, d: d}
+	file.FileName = file.Name
+
+	fs, err := d.resolveDict(obj)
+	if err != nil {
+		return file, fmt.Errorf("unable to resolve the file specification: %w", err)
+	}
+	// /UF is preferred over /F which is for compatibility
+	for _, key := range []string{"F", "UF"} {
+		if s, ok := fs[key].(PDFString); ok {
+			file.FileName = DecodeTextString(s)
+		}
+	}
+
+	ef, err := d.resolveDict(fs["EF"])
+	if err != nil {
+		return file, fmt.Errorf("unable to resolve /EF: %w", err)
+	}
+	var ref PDFRef
+	for _, key := range []string{"UF", "F"} {
+		if r, ok := ef[key].(PDFRef); ok {
+			ref = r
+		}
+	}
+	if ref == (PDFRef{}) {
+		return file, errors.New("/EF must have /F as an indirect reference to a stream")
+	}
+	file.ref = ref
+
+	stream, err := d.Resolve(ref)
+	if err != nil {
+		return file, err
+	}
+	s, ok := stream.(PDFStream)
+	if !ok {
+		return file, fmt.Errorf("embedded file must be a stream but got %T", stream)
+	}
+	if params, err := d.resolveDict(s.Dict["Params"]); err == nil {
+		if size, ok := params["Size"].(PDFInt); ok {
+			file.Size = int64(size)
+		}
+	}
+
+	return file, nil
+}
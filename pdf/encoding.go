@@ -0,0 +1,53 @@
+package pdf
+
+// Annex D Character Sets and Encodings
+// Only codes that differ from ASCII and ISO Latin-1 are listed.
+
+var winAnsiEncoding = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†',
+	0x87: '‡', 0x88: 'ˆ', 0x89: '‰', 0x8a: 'Š', 0x8b: '‹', 0x8c: 'Œ',
+	0x8e: 'Ž', 0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•',
+	0x96: '–', 0x97: '—', 0x98: '˜', 0x99: '™', 0x9a: 'š', 0x9b: '›',
+	0x9c: 'œ', 0x9e: 'ž', 0x9f: 'Ÿ',
+}
+
+// codes from 0x80 to 0xff not listed are undefined in StandardEncoding
+var standardEncoding = map[byte]rune{
+	0x27: '’', 0x60: '‘',
+	0xa1: '¡', 0xa2: '¢', 0xa3: '£', 0xa4: '⁄', 0xa5: '¥', 0xa6: 'ƒ',
+	0xa7: '§', 0xa8: '¤', 0xa9: '\'', 0xaa: '“', 0xab: '«', 0xac: '‹',
+	0xad: '›', 0xae: 'ﬁ', 0xaf: 'ﬂ', 0xb1: '–', 0xb2: '†', 0xb3: '‡',
+	0xb4: '·', 0xb6: '¶', 0xb7: '•', 0xb8: '‚', 0xb9: '„', 0xba: '”',
+	0xbb: '»', 0xbc: '…', 0xbd: '‰', 0xbf: '¿', 0xc1: '`', 0xc2: '´',
+	0xc3: 'ˆ', 0xc4: '˜', 0xc5: '¯', 0xc6: '˘', 0xc7: '˙', 0xc8: '¨',
+	0xca: '˚', 0xcb: '¸', 0xcd: '˝', 0xce: '˛', 0xcf: 'ˇ', 0xd0: '—',
+	0xe1: 'Æ', 0xe3: 'ª', 0xe8: 'Ł', 0xe9: 'Ø', 0xea: 'Œ', 0xeb: 'º',
+	0xf1: 'æ', 0xf5: 'ı', 0xf8: 'ł', 0xf9: 'ø', 0xfa: 'œ', 0xfb: 'ß',
+}
+
+// decodeSimple decodes b in a simple font encoding. ok is false when the encoding is not supported.
+func decodeSimple(encoding PDFName, b []byte) (string, bool) {
+	var table map[byte]rune
+	switch encoding {
+	case "WinAnsiEncoding":
+		table = winAnsiEncoding
+	case "StandardEncoding":
+		table = standardEncoding
+	default:
+		return "", false
+	}
+
+	rs := make([]rune, 0, len(b))
+	for _, c := range b {
+		if r, ok := table[c]; ok {
+			rs = append(rs, r)
+			continue
+		}
+		if encoding == "StandardEncoding" && c >= 0x80 {
+			// undefined
+			continue
+		}
+		rs = append(rs, rune(c))
+	}
+	return string(rs), true
+}
@@ -0,0 +1,151 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrEncrypted is returned when encrypted data is read without a password.
+var ErrEncrypted = errors.New("document is encrypted")
+
+// IsEncrypted reports whether the document has /Encrypt in the trailer and returns the encryption dictionary.
+func (d *Document) IsEncrypted() (bool, PDFDict, error) {
+	obj, ok := d.trailerDict["Encrypt"]
+	if !ok {
+		return false, nil, nil
+	}
+
+	resolved, err := d.Resolve(obj)
+	if err != nil {
+		return true, nil, fmt.Errorf("unable to resolve /Encrypt: %w", err)
+	}
+	dict, ok := resolved.(PDFDict)
+	if !ok {
+		return true, nil, fmt.Errorf("/Encrypt must be a dictionary but got %T", resolved)
+	}
+
+	return true, dict, nil
+}
+
+// 7.6.4 Standard Security Handler
+type StandardSecurity struct {
+	// V is the algorithm and R is the revision of the standard security handler
+	V int
+	R int
+
+	O []byte
+	U []byte
+	P int32
+
+	// Length is the length of the key in bits
+	Length int
+
+	EncryptMetadata bool
+
+	// 7.6.5 Crypt Filters (V 4 and 5)
+	CF   map[PDFName]CryptFilter
+	StmF PDFName
+	StrF PDFName
+
+	// R 5 and 6
+	OE    []byte
+	UE    []byte
+	Perms []byte
+}
+
+// Table 25 Entries common to all crypt filter dictionaries
+type CryptFilter struct {
+	// CFM is None, V2, AESV2 or AESV3
+	CFM PDFName
+	// Length is the length of the key in bytes
+	Length int
+}
+
+// ReadStandardSecurity reads parameters of the standard security handler from the encryption dictionary.
+func ReadStandardSecurity(dict PDFDict) (StandardSecurity, error) {
+	var sec StandardSecurity
+
+	if filter, _ := dict["Filter"].(PDFName); filter != "Standard" {
+		return sec, fmt.Errorf("%w: security handler %q", ErrUnsupportedEncryption, filter)
+	}
+
+	v, ok := dict["V"].(PDFInt)
+	if !ok {
+		// 0 is an undocumented algorithm that is no longer supported
+		v = 0
+	}
+	sec.V = int(v)
+
+	r, ok := dict["R"].(PDFInt)
+	if !ok {
+		return sec, errors.New("standard security handler must have /R")
+	}
+	sec.R = int(r)
+
+	o, ok := dict["O"].(PDFString)
+	if !ok {
+		return sec, errors.New("standard security handler must have /O")
+	}
+	sec.O = []byte(o)
+
+	u, ok := dict["U"].(PDFString)
+	if !ok {
+		return sec, errors.New("standard security handler must have /U")
+	}
+	sec.U = []byte(u)
+
+	p, ok := dict["P"].(PDFInt)
+	if !ok {
+		return sec, errors.New("standard security handler must have /P")
+	}
+	sec.P = int32(p)
+
+	sec.Length = 40
+	if length, ok := dict["Length"].(PDFInt); ok {
+		sec.Length = int(length)
+	}
+
+	sec.EncryptMetadata = true
+	if b, ok := dict["EncryptMetadata"].(PDFBool); ok {
+		sec.EncryptMetadata = bool(b)
+	}
+
+	if sec.V >= 4 {
+		sec.CF = map[PDFName]CryptFilter{}
+		cf, _ := dict["CF"].(PDFDict)
+		for name, obj := range cf {
+			fd, ok := obj.(PDFDict)
+			if !ok {
+				return sec, fmt.Errorf("crypt filter /%s must be a dictionary", name)
+			}
+			filter := CryptFilter{CFM: "None"}
+			if cfm, ok := fd["CFM"].(PDFName); ok {
+				filter.CFM = cfm
+			}
+			if length, ok := fd["Length"].(PDFInt); ok {
+				filter.Length = int(length)
+			}
+			sec.CF[PDFName(name)] = filter
+		}
+
+		sec.StmF, sec.StrF = "Identity", "Identity"
+		if name, ok := dict["StmF"].(PDFName); ok {
+			sec.StmF = name
+		}
+		if name, ok := dict["StrF"].(PDFName); ok {
+			sec.StrF = name
+		}
+	}
+
+	if sec.R >= 5 {
+		for key, dst := range map[string]*[]byte{"OE": &sec.OE, "UE": &sec.UE, "Perms": &sec.Perms} {
+			b, ok := dict[key].(PDFString)
+			if !ok {
+				return sec, fmt.Errorf("standard security handler must have /%s", key)
+			}
+			*dst = []byte(b)
+		}
+	}
+
+	return sec, nil
+}
@@ -0,0 +1,41 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ID returns the original and the current file identifiers in /ID of the newest trailer.
+// 14.4 File Identifiers
+func (t Trailer) ID() ([][]byte, error) {
+	if t.Dict != nil {
+		return fileID(t.Dict)
+	}
+	_, dict, err := readXrefSection(t.ra, t.StartXref, t.Size, t.Strict)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the trailer dictionary: %w", err)
+	}
+	return fileID(dict)
+}
+
+// fileID returns the elements of /ID in dict. Hexadecimal strings are already decoded by the lexer.
+func fileID(dict PDFDict) ([][]byte, error) {
+	obj, ok := dict["ID"]
+	if !ok {
+		return nil, errors.New("trailer has no /ID")
+	}
+	ids, ok := obj.(PDFArray)
+	if !ok || len(ids) != 2 {
+		return nil, fmt.Errorf("/ID must be an array of two strings but got %s", renderObject(obj))
+	}
+
+	var id [][]byte
+	for _, elem := range ids {
+		s, ok := elem.(PDFString)
+		if !ok {
+			return nil, fmt.Errorf("/ID must be an array of two strings but got %T", elem)
+		}
+		id = append(id, []byte(s))
+	}
+	return id, nil
+}
@@ -0,0 +1,316 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxDecodedSize is the default limit of the size of a decoded stream.
+const DefaultMaxDecodedSize = 256 << 20
+
+// ErrDecodedSizeExceeded is returned when a decoded stream is larger than the limit.
+// A small compressed stream can expand to gigabytes.
+var ErrDecodedSizeExceeded = errors.New("decoded stream exceeds the size limit")
+
+// ErrUnsupportedFilter is returned when a stream has a filter which cannot be decoded.
+var ErrUnsupportedFilter = errors.New("unsupported filter")
+
+// DecodeStream decodes raw stream data according to /Filter and /DecodeParms in the stream dictionary.
+// Filters are applied in order when /Filter is an array. The abbreviated names of inline images such as /Fl are accepted.
+// ErrDecodedSizeExceeded is returned when the output of any filter exceeds DefaultMaxDecodedSize.
+func DecodeStream(dict PDFDict, raw []byte) ([]byte, error) {
+	return DecodeStreamWithLimit(dict, raw, DefaultMaxDecodedSize)
+}
+
+// DecodeStreamWithLimit is the same as DecodeStream but with the limit of the decoded size in bytes.
+// A negative limit means no limit.
+func DecodeStreamWithLimit(dict PDFDict, raw []byte, limit int64) ([]byte, error) {
+	filters, parms, err := streamFilters(dict)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range filters {
+		if _, ok := filterDecoders[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedFilter, name)
+		}
+	}
+
+	b := raw
+	for i, name := range filters {
+		b, err = filterDecoders[name](b, parms[i], limit)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode %s: %w", name, err)
+		}
+		// filters which cannot expand much are checked only here
+		if limit >= 0 && int64(len(b)) > limit {
+			return nil, fmt.Errorf("unable to decode %s: %w", name, ErrDecodedSizeExceeded)
+		}
+	}
+
+	return b, nil
+}
+
+// filterDecoder decodes b. It should stop as soon as the output exceeds limit
+// if the output can be much larger than b. A negative limit means no limit.
+type filterDecoder func(b []byte, parms PDFDict, limit int64) ([]byte, error)
+
+// 7.4 Filters
+var filterDecoders = map[PDFName]filterDecoder{
+	"ASCIIHexDecode":  asciiHexDecode,
+	"ASCII85Decode":   ascii85Decode,
+	"FlateDecode":     flateDecode,
+	"LZWDecode":       lzwDecode,
+	"RunLengthDecode": runLengthDecode,
+	"CCITTFaxDecode":  ccittFaxDecode,
+	// the security handler decrypts the stream before decoding
+	"Crypt": func(b []byte, _ PDFDict, _ int64) ([]byte, error) { return b, nil },
+}
+
+// streamFilters returns /Filter and matching /DecodeParms as slices of the same length.
+// A parameter is nil when the filter has no parameters. Abbreviated filter names are replaced with the full names.
+func streamFilters(dict PDFDict) ([]PDFName, []PDFDict, error) {
+	var filters []PDFName
+	switch filter := dict["Filter"].(type) {
+	case nil:
+		return nil, nil, nil
+	case PDFName:
+		filters = []PDFName{canonicalFilter(filter)}
+	case PDFArray:
+		for i := range filter {
+			name, ok := filter[i].(PDFName)
+			if !ok {
+				return nil, nil, fmt.Errorf("/Filter must be an array of names but got %v", filter[i])
+			}
+			filters = append(filters, canonicalFilter(name))
+		}
+	default:
+		return nil, nil, fmt.Errorf("/Filter must be a name or an array but got %v", filter)
+	}
+
+	parms := make([]PDFDict, len(filters))
+	switch decodeParms := dict["DecodeParms"].(type) {
+	case nil, PDFNull:
+	case PDFDict:
+		parms[0] = decodeParms
+	case PDFArray:
+		if len(decodeParms) != len(filters) {
+			return nil, nil, fmt.Errorf("/DecodeParms has %d entries but /Filter has %d", len(decodeParms), len(filters))
+		}
+		for i := range decodeParms {
+			switch p := decodeParms[i].(type) {
+			case PDFDict:
+				parms[i] = p
+			case PDFNull:
+			default:
+				return nil, nil, fmt.Errorf("/DecodeParms must be an array of dictionaries but got %v", p)
+			}
+		}
+	default:
+		return nil, nil, fmt.Errorf("/DecodeParms must be a dictionary or an array but got %v", decodeParms)
+	}
+
+	return filters, parms, nil
+}
+
+// 7.4.4 LZWDecode and FlateDecode Filters
+func flateDecode(raw []byte, parms PDFDict, limit int64) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize zlib: %w", err)
+	}
+	defer zr.Close()
+
+	b, err := io.ReadAll(newSizeLimitReader(zr, limit))
+	if err != nil {
+		return nil, fmt.Errorf("unable to inflate: %w", err)
+	}
+
+	return applyPredictorParms(b, parms)
+}
+
+// applyPredictorParms reverses prediction described by /DecodeParms if any.
+func applyPredictorParms(b []byte, parms PDFDict) ([]byte, error) {
+	intParm := func(key string, def int) int {
+		if n, ok := parms[key].(PDFInt); ok {
+			return int(n)
+		}
+		return def
+	}
+
+	return ApplyPredictor(
+		b,
+		intParm("Predictor", 1),
+		intParm("Colors", 1),
+		intParm("BitsPerComponent", 8),
+		intParm("Columns", 1),
+	)
+}
+
+// ApplyPredictor reverses the prediction of data with the parameters in /DecodeParms.
+// predictor is 1 for no prediction, 2 for TIFF Predictor 2 or 10 to 15 for the PNG predictors
+// where each row has its filter type so that 15 (PNG optimum) is decoded as any of them.
+// Table 8 Optional parameters for LZWDecode and FlateDecode filters
+func ApplyPredictor(b []byte, predictor, colors, bpc, columns int) ([]byte, error) {
+	if predictor == 1 {
+		return b, nil
+	}
+	if colors < 1 || columns < 1 {
+		return nil, fmt.Errorf("invalid predictor parameters: /Colors %d /Columns %d", colors, columns)
+	}
+	switch bpc {
+	case 1, 2, 4, 8, 16:
+	default:
+		return nil, fmt.Errorf("invalid predictor parameter: /BitsPerComponent %d", bpc)
+	}
+
+	// bytes per pixel and per row
+	bpp := (colors*bpc + 7) / 8
+	rowLen := (colors*bpc*columns + 7) / 8
+
+	switch {
+	case predictor == 2:
+		return applyTIFFPredictor(b, colors, bpc, rowLen), nil
+	case predictor >= 10 && predictor <= 15:
+		return applyPNGPredictor(b, bpp, rowLen)
+	}
+	return nil, fmt.Errorf("unsupported predictor: %d", predictor)
+}
+
+// applyTIFFPredictor reverses TIFF Predictor 2 where each sample is a difference from the left one.
+func applyTIFFPredictor(b []byte, colors, bpc, rowLen int) []byte {
+	out := make([]byte, len(b))
+	copy(out, b)
+
+	for start := 0; start+rowLen <= len(out); start += rowLen {
+		row := out[start : start+rowLen]
+
+		switch bpc {
+		case 8:
+			for i := colors; i < len(row); i++ {
+				row[i] += row[i-colors]
+			}
+		case 16:
+			for i := colors * 2; i+1 < len(row); i += 2 {
+				v := uint16(row[i])<<8 | uint16(row[i+1])
+				left := uint16(row[i-colors*2])<<8 | uint16(row[i-colors*2+1])
+				v += left
+				row[i], row[i+1] = byte(v>>8), byte(v)
+			}
+		default:
+			// samples smaller than a byte
+			mask := byte(1<<uint(bpc) - 1)
+			samples := rowLen * 8 / bpc
+			sample := func(i int) byte {
+				shift := uint(8 - bpc - (i*bpc)%8)
+				return row[i*bpc/8] >> shift & mask
+			}
+			for i := colors; i < samples; i++ {
+				v := (sample(i) + sample(i-colors)) & mask
+				shift := uint(8 - bpc - (i*bpc)%8)
+				row[i*bpc/8] = row[i*bpc/8]&^(mask<<shift) | v<<shift
+			}
+		}
+	}
+
+	return out
+}
+
+// applyPNGPredictor reverses PNG prediction where each row is prefixed by its filter type.
+func applyPNGPredictor(b []byte, bpp, rowLen int) ([]byte, error) {
+	out := make([]byte, 0, len(b)/(rowLen+1)*rowLen)
+	prev := make([]byte, rowLen)
+	for i := 0; i+1 < len(b); i += rowLen + 1 {
+		filter := b[i]
+		end := i + 1 + rowLen
+		if end > len(b) {
+			// tolerate a truncated last row
+			end = len(b)
+		}
+		row := b[i+1 : end]
+
+		cur := make([]byte, rowLen)
+		for j := range row {
+			var left, upperLeft byte
+			if j >= bpp {
+				left, upperLeft = cur[j-bpp], prev[j-bpp]
+			}
+			up := prev[j]
+
+			switch filter {
+			case 0:
+				cur[j] = row[j]
+			case 1:
+				cur[j] = row[j] + left
+			case 2:
+				cur[j] = row[j] + up
+			case 3:
+				cur[j] = row[j] + byte((int(left)+int(up))/2)
+			case 4:
+				cur[j] = row[j] + paeth(left, up, upperLeft)
+			default:
+				return nil, fmt.Errorf("unknown PNG filter type %d", filter)
+			}
+		}
+		out = append(out, cur[:len(row)]...)
+		prev = cur
+	}
+
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// sizeLimitReader is io.LimitedReader but returns ErrDecodedSizeExceeded instead of EOF
+// when r has more than n bytes.
+type sizeLimitReader struct {
+	r io.Reader
+	n int64
+}
+
+// newSizeLimitReader returns r as-is if limit is negative.
+func newSizeLimitReader(r io.Reader, limit int64) io.Reader {
+	if limit < 0 {
+		return r
+	}
+	return &sizeLimitReader{r: r, n: limit}
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		// see if there is more
+		var b [1]byte
+		if n, err := l.r.Read(b[:]); n > 0 {
+			return 0, ErrDecodedSizeExceeded
+		} else if err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
@@ -0,0 +1,123 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 7.4.2 ASCIIHexDecode Filter
+func asciiHexDecode(b []byte, _ PDFDict, _ int64) ([]byte, error) {
+	var out []byte
+	var hi byte
+	odd := false
+	for _, c := range b {
+		if c == '>' {
+			break
+		}
+		if isWhitespace(c) {
+			continue
+		}
+
+		v, ok := hexValue(c)
+		if !ok {
+			return nil, fmt.Errorf("invalid hex digit %q", c)
+		}
+
+		if odd {
+			out = append(out, hi<<4|v)
+		} else {
+			hi = v
+		}
+		odd = !odd
+	}
+
+	// an odd final digit is treated as followed by 0
+	if odd {
+		out = append(out, hi<<4)
+	}
+
+	return out, nil
+}
+
+func hexValue(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// 7.4.3 ASCII85Decode Filter
+func ascii85Decode(b []byte, _ PDFDict, _ int64) ([]byte, error) {
+	var out []byte
+	var group [5]byte
+	n := 0
+
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		if c == '~' {
+			// EOD is ~>
+			break
+		}
+		if isWhitespace(c) {
+			continue
+		}
+
+		if c == 'z' {
+			if n != 0 {
+				return nil, errors.New("z in the middle of a group")
+			}
+			out = append(out, 0, 0, 0, 0)
+			continue
+		}
+
+		if c < '!' || c > 'u' {
+			return nil, fmt.Errorf("invalid ASCII85 character %q", c)
+		}
+
+		group[n] = c - '!'
+		n++
+		if n == len(group) {
+			v, err := ascii85Value(group)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+			n = 0
+		}
+	}
+
+	switch n {
+	case 0:
+	case 1:
+		return nil, errors.New("final ASCII85 group must have at least 2 characters")
+	default:
+		// pad a partial group with u (84) and drop the corresponding bytes
+		for i := n; i < len(group); i++ {
+			group[i] = 'u' - '!'
+		}
+		v, err := ascii85Value(group)
+		if err != nil {
+			return nil, err
+		}
+		full := []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+		out = append(out, full[:n-1]...)
+	}
+
+	return out, nil
+}
+
+func ascii85Value(group [5]byte) (uint32, error) {
+	var v uint64
+	for _, d := range group {
+		v = v*85 + uint64(d)
+	}
+	if v > 0xffffffff {
+		return 0, errors.New("ASCII85 group overflows")
+	}
+	return uint32(v), nil
+}
@@ -0,0 +1,82 @@
+package pdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestASCIIHexDecode(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []byte
+		err  bool
+	}{
+		{in: "48656C6C6F>", want: []byte("Hello")},
+		{in: "48656c6c6f>", want: []byte("Hello")},
+		// whitespace is ignored
+		{in: "48 65\n6C\t6C\r\n6F >", want: []byte("Hello")},
+		// 7.4.2 an odd final digit is as if followed by 0
+		{in: "901FA>", want: []byte{0x90, 0x1f, 0xa0}},
+		// the data ends at > or the end of the stream
+		{in: "41>42", want: []byte("A")},
+		{in: "4142", want: []byte("AB")},
+		{in: ">", want: nil},
+		{in: "4G>", err: true},
+	} {
+		got, err := DecodeStream(PDFDict{"Filter": PDFName("ASCIIHexDecode")}, []byte(tc.in))
+		if tc.err {
+			if err == nil {
+				t.Errorf("%q: expected an error but got %q", tc.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tc.in, err)
+			continue
+		}
+		if !bytes.Equal(got, tc.want) {
+			t.Errorf("%q: got %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestASCII85Decode(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []byte
+		err  bool
+	}{
+		{in: "9jqo^BlbD-BleB1DJ+*+F(f,q~>", want: []byte("Man is distinguished")},
+		{in: "9jqo^~>", want: []byte("Man ")},
+		// whitespace is ignored
+		{in: "9jq\no^ ~>", want: []byte("Man ")},
+		// z is four zero bytes
+		{in: "z~>", want: []byte{0, 0, 0, 0}},
+		{in: "9jqo^z9jqo^~>", want: []byte("Man \x00\x00\x00\x00Man ")},
+		// a final partial group of n characters is n-1 bytes
+		{in: "9jqo~>", want: []byte("Man")},
+		{in: "9jn~>", want: []byte("Ma")},
+		{in: "9`~>", want: []byte("M")},
+		{in: "s8W-!~>", want: []byte{0xff, 0xff, 0xff, 0xff}},
+		{in: "~>", want: nil},
+		{in: "9~>", err: true},
+		{in: "9jzqo~>", err: true},
+		{in: "uuuuu~>", err: true},
+		{in: "9jqv^~>", err: true},
+	} {
+		got, err := DecodeStream(PDFDict{"Filter": PDFName("ASCII85Decode")}, []byte(tc.in))
+		if tc.err {
+			if err == nil {
+				t.Errorf("%q: expected an error but got %q", tc.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tc.in, err)
+			continue
+		}
+		if !bytes.Equal(got, tc.want) {
+			t.Errorf("%q: got %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,414 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 7.4.6 CCITTFaxDecode Filter
+// The codes are defined in ITU-T T.4 and T.6. The data ends at EOFB or RTC whether /EndOfBlock is set or not.
+// Uncompressed mode is not supported.
+func ccittFaxDecode(b []byte, parms PDFDict, limit int64) ([]byte, error) {
+	p := ccittParams{columns: 1728}
+	if n, ok := parms["K"].(PDFInt); ok {
+		p.k = int(n)
+	}
+	if n, ok := parms["Columns"].(PDFInt); ok {
+		p.columns = int(n)
+	}
+	if n, ok := parms["Rows"].(PDFInt); ok {
+		p.rows = int(n)
+	}
+	if v, ok := parms["EncodedByteAlign"].(PDFBool); ok {
+		p.byteAlign = bool(v)
+	}
+	if v, ok := parms["BlackIs1"].(PDFBool); ok {
+		p.blackIs1 = bool(v)
+	}
+	if p.columns <= 0 || p.columns > 1<<16 {
+		return nil, fmt.Errorf("invalid /Columns: %d", p.columns)
+	}
+	if p.rows < 0 {
+		return nil, fmt.Errorf("invalid /Rows: %d", p.rows)
+	}
+	return decodeCCITT(b, p, limit)
+}
+
+type ccittParams struct {
+	// k < 0 is Group 4, k == 0 is Group 3 1-D and k > 0 is Group 3 mixed 1-D and 2-D
+	k         int
+	columns   int
+	rows      int
+	byteAlign bool
+	blackIs1  bool
+}
+
+var errCCITTEnd = errors.New("end of CCITT data")
+
+// decodeCCITT decodes rows of 1 bit per pixel packed into bytes. Each row starts at a byte boundary.
+// A row is a list of changing elements, the positions where the color changes from the previous pixel.
+// The first change is from white to black.
+func decodeCCITT(b []byte, p ccittParams, limit int64) ([]byte, error) {
+	br := &ccittBitReader{b: b}
+	rowBytes := (p.columns + 7) / 8
+
+	var out []byte
+	// the imaginary row above the first row is white
+	ref := []int{p.columns, p.columns}
+	for row := 0; p.rows == 0 || row < p.rows; row++ {
+		if br.eof() {
+			break
+		}
+		if p.k < 0 {
+			// EOFB may not be aligned even if rows are
+			if br.peek(24) == 0x001001 {
+				break
+			}
+			if p.byteAlign {
+				br.align()
+			}
+		}
+
+		twoD := p.k < 0
+		if p.k >= 0 {
+			if p.byteAlign {
+				br.align()
+			}
+			eol := br.skipEOL()
+			if eol && br.atEOL() {
+				// 7.4.6 RTC is six EOLs and ends the data
+				break
+			}
+			if p.k > 0 {
+				twoD = br.read(1) == 0
+			}
+		}
+
+		var changes []int
+		var err error
+		if twoD {
+			changes, err = br.decode2DRow(ref, p.columns)
+		} else {
+			changes, err = br.decode1DRow(p.columns)
+		}
+		if err == errCCITTEnd {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode row %d: %w", row, err)
+		}
+
+		out = append(out, ccittRowBits(changes, p.columns, rowBytes, p.blackIs1)...)
+		if limit >= 0 && int64(len(out)) > limit {
+			return nil, ErrDecodedSizeExceeded
+		}
+		ref = append(changes, p.columns, p.columns)
+	}
+	return out, nil
+}
+
+// ccittRowBits packs a row. 0 is black unless blackIs1.
+func ccittRowBits(changes []int, columns, rowBytes int, blackIs1 bool) []byte {
+	row := make([]byte, rowBytes)
+	// black runs are from changes[i] to changes[i+1] for even i
+	for i := 0; i < len(changes); i += 2 {
+		end := columns
+		if i+1 < len(changes) {
+			end = changes[i+1]
+		}
+		for x := changes[i]; x < end; x++ {
+			row[x/8] |= 0x80 >> uint(x%8)
+		}
+	}
+	if !blackIs1 {
+		for i := range row {
+			row[i] = ^row[i]
+		}
+	}
+	return row
+}
+
+// decode1DRow decodes a row of alternating white and black runs (Modified Huffman).
+func (br *ccittBitReader) decode1DRow(columns int) ([]int, error) {
+	var changes []int
+	white := true
+	for pos := 0; pos < columns; {
+		run, err := br.readRun(white)
+		if err != nil {
+			return nil, err
+		}
+		pos += run
+		if pos > columns {
+			pos = columns
+		}
+		changes = append(changes, pos)
+		white = !white
+	}
+	// a row which ends with white has no change at the end
+	if len(changes) > 0 && changes[len(changes)-1] == columns {
+		changes = changes[:len(changes)-1]
+	}
+	return changes, nil
+}
+
+// decode2DRow decodes a row coded relative to the reference row (Modified READ).
+// ref ends with two sentinels at columns.
+func (br *ccittBitReader) decode2DRow(ref []int, columns int) ([]int, error) {
+	var changes []int
+	a0 := -1
+	white := true
+	i := 0
+	for a0 < columns {
+		// b1 is the first change on the reference row after a0 to the opposite color of a0.
+		// Changes at even indexes are to black.
+		for i > 0 && ref[i-1] > a0 {
+			i--
+		}
+		for i < len(ref)-2 && (ref[i] <= a0 || (i%2 == 0) != white) {
+			i++
+		}
+		b1, b2 := ref[i], ref[i+1]
+
+		mode, err := br.readMode()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case mode == ccittPass:
+			a0 = b2
+		case mode == ccittHorizontal:
+			start := a0
+			if start < 0 {
+				start = 0
+			}
+			run1, err := br.readRun(white)
+			if err != nil {
+				return nil, err
+			}
+			run2, err := br.readRun(!white)
+			if err != nil {
+				return nil, err
+			}
+			a1 := clampColumn(start+run1, columns)
+			a2 := clampColumn(a1+run2, columns)
+			changes = append(changes, a1, a2)
+			a0 = a2
+		default:
+			// vertical mode from -3 to 3
+			a1 := b1 + mode
+			if a1 < 0 || a1 > columns || a1 < a0 {
+				return nil, fmt.Errorf("invalid vertical mode %d at %d", mode, b1)
+			}
+			changes = append(changes, a1)
+			a0 = a1
+			white = !white
+		}
+	}
+
+	// drop changes at the end of the row
+	for len(changes) > 0 && changes[len(changes)-1] >= columns {
+		changes = changes[:len(changes)-1]
+	}
+	return changes, nil
+}
+
+func clampColumn(x, columns int) int {
+	if x > columns {
+		return columns
+	}
+	return x
+}
+
+const (
+	ccittPass       = 100
+	ccittHorizontal = 101
+)
+
+// Table 4/T.4 Two-dimensional code table
+var ccittModeCodes = map[ccittCode]int{
+	{4, 0x1}: ccittPass,
+	{3, 0x1}: ccittHorizontal,
+	{1, 0x1}: 0,
+	{3, 0x3}: 1,
+	{6, 0x3}: 2,
+	{7, 0x3}: 3,
+	{3, 0x2}: -1,
+	{6, 0x2}: -2,
+	{7, 0x2}: -3,
+}
+
+func (br *ccittBitReader) readMode() (int, error) {
+	for n := 1; n <= 7; n++ {
+		if mode, ok := ccittModeCodes[ccittCode{n, br.peek(n)}]; ok {
+			br.pos += n
+			return mode, nil
+		}
+	}
+	if br.atEnd() {
+		// EOFB is two EOLs
+		return 0, errCCITTEnd
+	}
+	return 0, errors.New("unsupported 2-D mode code")
+}
+
+// readRun reads makeup codes and a terminating code of a run of the color.
+func (br *ccittBitReader) readRun(white bool) (int, error) {
+	codes := ccittBlackCodes
+	if white {
+		codes = ccittWhiteCodes
+	}
+
+	total := 0
+	for {
+		run := -1
+		for n := 2; n <= 13; n++ {
+			if r, ok := codes[ccittCode{n, br.peek(n)}]; ok {
+				br.pos += n
+				run = r
+				break
+			}
+		}
+		if run < 0 {
+			if br.atEnd() {
+				return 0, errCCITTEnd
+			}
+			return 0, errors.New("invalid run length code")
+		}
+		total += run
+		if run < 64 {
+			return total, nil
+		}
+	}
+}
+
+type ccittCode struct {
+	length int
+	code   uint32
+}
+
+type ccittBitReader struct {
+	b []byte
+	// pos is the position in bits
+	pos int
+}
+
+// peek returns the next n bits. Bits after the end are 0.
+func (br *ccittBitReader) peek(n int) uint32 {
+	var v uint32
+	for i := 0; i < n; i++ {
+		v <<= 1
+		if p := br.pos + i; p/8 < len(br.b) && br.b[p/8]&(0x80>>uint(p%8)) != 0 {
+			v |= 1
+		}
+	}
+	return v
+}
+
+func (br *ccittBitReader) read(n int) uint32 {
+	v := br.peek(n)
+	br.pos += n
+	return v
+}
+
+func (br *ccittBitReader) eof() bool {
+	return br.pos >= len(br.b)*8
+}
+
+func (br *ccittBitReader) align() {
+	br.pos = (br.pos + 7) / 8 * 8
+}
+
+// atEnd returns true at the end of data, EOL or padding of zeros to the end.
+func (br *ccittBitReader) atEnd() bool {
+	if br.atEOL() {
+		return true
+	}
+	for p := br.pos; p < len(br.b)*8; p++ {
+		if br.b[p/8]&(0x80>>uint(p%8)) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// atEOL returns true when EOL (000000000001) follows.
+func (br *ccittBitReader) atEOL() bool {
+	return !br.eof() && br.peek(12) == 1
+}
+
+// skipEOL skips fill bits and EOL if they follow.
+func (br *ccittBitReader) skipEOL() bool {
+	zeros := 0
+	for br.pos+zeros < len(br.b)*8 && br.peek(zeros+1)&1 == 0 {
+		zeros++
+	}
+	if zeros < 11 || br.pos+zeros >= len(br.b)*8 {
+		return false
+	}
+	br.pos += zeros + 1
+	return true
+}
+
+// Table 2/T.4 Terminating codes and Table 3/T.4 Make-up codes
+var (
+	ccittWhiteCodes = ccittCodeTable(ccittWhiteRuns, ccittExtendedMakeup)
+	ccittBlackCodes = ccittCodeTable(ccittBlackRuns, ccittExtendedMakeup)
+)
+
+func ccittCodeTable(tables ...map[int]string) map[ccittCode]int {
+	m := map[ccittCode]int{}
+	for _, table := range tables {
+		for run, code := range table {
+			var v uint32
+			for _, c := range code {
+				v = v<<1 | uint32(c-'0')
+			}
+			m[ccittCode{len(code), v}] = run
+		}
+	}
+	return m
+}
+
+var ccittWhiteRuns = map[int]string{
+	0: "00110101", 1: "000111", 2: "0111", 3: "1000", 4: "1011", 5: "1100", 6: "1110", 7: "1111",
+	8: "10011", 9: "10100", 10: "00111", 11: "01000", 12: "001000", 13: "000011", 14: "110100", 15: "110101",
+	16: "101010", 17: "101011", 18: "0100111", 19: "0001100", 20: "0001000", 21: "0010111", 22: "0000011", 23: "0000100",
+	24: "0101000", 25: "0101011", 26: "0010011", 27: "0100100", 28: "0011000", 29: "00000010", 30: "00000011", 31: "00011010",
+	32: "00011011", 33: "00010010", 34: "00010011", 35: "00010100", 36: "00010101", 37: "00010110", 38: "00010111", 39: "00101000",
+	40: "00101001", 41: "00101010", 42: "00101011", 43: "00101100", 44: "00101101", 45: "00000100", 46: "00000101", 47: "00001010",
+	48: "00001011", 49: "01010010", 50: "01010011", 51: "01010100", 52: "01010101", 53: "00100100", 54: "00100101", 55: "01011000",
+	56: "01011001", 57: "01011010", 58: "01011011", 59: "01001010", 60: "01001011", 61: "00110010", 62: "00110011", 63: "00110100",
+
+	64: "11011", 128: "10010", 192: "010111", 256: "0110111", 320: "00110110", 384: "00110111", 448: "01100100", 512: "01100101",
+	576: "01101000", 640: "01100111", 704: "011001100", 768: "011001101", 832: "011010010", 896: "011010011", 960: "011010100",
+	1024: "011010101", 1088: "011010110", 1152: "011010111", 1216: "011011000", 1280: "011011001", 1344: "011011010",
+	1408: "011011011", 1472: "010011000", 1536: "010011001", 1600: "010011010", 1664: "011000", 1728: "010011011",
+}
+
+var ccittBlackRuns = map[int]string{
+	0: "0000110111", 1: "010", 2: "11", 3: "10", 4: "011", 5: "0011", 6: "0010", 7: "00011",
+	8: "000101", 9: "000100", 10: "0000100", 11: "0000101", 12: "0000111", 13: "00000100", 14: "00000111", 15: "000011000",
+	16: "0000010111", 17: "0000011000", 18: "0000001000", 19: "00001100111", 20: "00001101000", 21: "00001101100",
+	22: "00000110111", 23: "00000101000", 24: "00000010111", 25: "00000011000", 26: "000011001010", 27: "000011001011",
+	28: "000011001100", 29: "000011001101", 30: "000001101000", 31: "000001101001", 32: "000001101010", 33: "000001101011",
+	34: "000011010010", 35: "000011010011", 36: "000011010100", 37: "000011010101", 38: "000011010110", 39: "000011010111",
+	40: "000001101100", 41: "000001101101", 42: "000011011010", 43: "000011011011", 44: "000001010100", 45: "000001010101",
+	46: "000001010110", 47: "000001010111", 48: "000001100100", 49: "000001100101", 50: "000001010010", 51: "000001010011",
+	52: "000000100100", 53: "000000110111", 54: "000000111000", 55: "000000100111", 56: "000000101000", 57: "000001011000",
+	58: "000001011001", 59: "000000101011", 60: "000000101100", 61: "000001011010", 62: "000001100110", 63: "000001100111",
+
+	64: "0000001111", 128: "000011001000", 192: "000011001001", 256: "000001011011", 320: "000000110011",
+	384: "000000110100", 448: "000000110101", 512: "0000001101100", 576: "0000001101101", 640: "0000001001010",
+	704: "0000001001011", 768: "0000001001100", 832: "0000001001101", 896: "0000001110010", 960: "0000001110011",
+	1024: "0000001110100", 1088: "0000001110101", 1152: "0000001110110", 1216: "0000001110111", 1280: "0000001010010",
+	1344: "0000001010011", 1408: "0000001010100", 1472: "0000001010101", 1536: "0000001011010", 1600: "0000001011011",
+	1664: "0000001100100", 1728: "0000001100101",
+}
+
+// Table 3a/T.4 Extended make-up codes shared by both colors
+var ccittExtendedMakeup = map[int]string{
+	1792: "00000001000", 1856: "00000001100", 1920: "00000001101", 1984: "000000010010", 2048: "000000010011",
+	2112: "000000010100", 2176: "000000010101", 2240: "000000010110", 2304: "000000010111", 2368: "000000011100",
+	2432: "000000011101", 2496: "000000011110", 2560: "000000011111",
+}
@@ -0,0 +1,110 @@
+package pdf
+
+import (
+	"fmt"
+)
+
+const (
+	lzwClearTable = 256
+	lzwEOD        = 257
+	lzwFirstCode  = 258
+	lzwMaxWidth   = 12
+)
+
+// 7.4.4 LZWDecode and FlateDecode Filters
+func lzwDecode(b []byte, parms PDFDict, limit int64) ([]byte, error) {
+	earlyChange := 1
+	if n, ok := parms["EarlyChange"].(PDFInt); ok {
+		earlyChange = int(n)
+	}
+	if earlyChange != 0 && earlyChange != 1 {
+		return nil, fmt.Errorf("invalid /EarlyChange: %d", earlyChange)
+	}
+
+	out, err := decodeLZW(b, earlyChange, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyPredictorParms(out, parms)
+}
+
+func decodeLZW(b []byte, earlyChange int, limit int64) ([]byte, error) {
+	var table [][]byte
+	resetTable := func() {
+		table = table[:0]
+		for i := 0; i < lzwFirstCode; i++ {
+			table = append(table, []byte{byte(i)})
+		}
+	}
+	resetTable()
+
+	var out, prev []byte
+	var bits uint32
+	var nbits uint
+	width := uint(9)
+
+	for i := 0; ; {
+		// fill bits for the next code
+		for nbits < width && i < len(b) {
+			bits = bits<<8 | uint32(b[i])
+			nbits += 8
+			i++
+		}
+		if nbits < width {
+			// no EOD but we have consumed all the data
+			return out, nil
+		}
+		code := int(bits >> (nbits - width) & (1<<width - 1))
+		nbits -= width
+
+		switch code {
+		case lzwClearTable:
+			resetTable()
+			width = 9
+			prev = nil
+			continue
+		case lzwEOD:
+			return out, nil
+		}
+
+		var entry []byte
+		switch {
+		case prev == nil:
+			if code >= lzwClearTable {
+				return nil, fmt.Errorf("invalid first LZW code %d", code)
+			}
+			entry = table[code]
+		case code < len(table):
+			entry = table[code]
+			table = appendLZWEntry(table, prev, entry[0])
+		case code == len(table):
+			table = appendLZWEntry(table, prev, prev[0])
+			entry = table[code]
+		default:
+			return nil, fmt.Errorf("invalid LZW code %d", code)
+		}
+
+		out = append(out, entry...)
+		prev = entry
+		if limit >= 0 && int64(len(out)) > limit {
+			return nil, ErrDecodedSizeExceeded
+		}
+
+		// the code width grows one code early by default
+		if len(table)+earlyChange >= 1<<width && width < lzwMaxWidth {
+			width++
+		}
+	}
+}
+
+func appendLZWEntry(table [][]byte, prefix []byte, c byte) [][]byte {
+	if len(table) >= 1<<lzwMaxWidth {
+		// the table is full. The encoder should emit a clear-table code.
+		return table
+	}
+	entry := make([]byte, len(prefix)+1)
+	copy(entry, prefix)
+	entry[len(prefix)] = c
+	return append(table, entry)
+}
@@ -0,0 +1,140 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/lzw"
+	"math/rand"
+	"testing"
+)
+
+// encodeLZW encodes b with the code width growing one code early when earlyChange is 1.
+// The table must not fill up since no clear-table code is emitted in the middle.
+func encodeLZW(b []byte, earlyChange int) (data []byte, codes int) {
+	var out bytes.Buffer
+	var bits uint32
+	var nbits uint
+	width := uint(9)
+	write := func(code int) {
+		bits = bits<<width | uint32(code)
+		nbits += width
+		for nbits >= 8 {
+			out.WriteByte(byte(bits >> (nbits - 8)))
+			nbits -= 8
+		}
+		codes++
+	}
+
+	dict := map[string]int{}
+	for i := 0; i < 256; i++ {
+		dict[string([]byte{byte(i)})] = i
+	}
+	next := lzwFirstCode
+	// decoded is the size of the table of the decoder which adds an entry from the second code
+	decoded, first := lzwFirstCode, true
+	emit := func(code int) {
+		write(code)
+		if !first {
+			decoded++
+		}
+		first = false
+		if decoded+earlyChange >= 1<<width && width < lzwMaxWidth {
+			width++
+		}
+	}
+
+	write(lzwClearTable)
+	var w []byte
+	for _, c := range b {
+		wc := append(append([]byte(nil), w...), c)
+		if _, ok := dict[string(wc)]; ok {
+			w = wc
+			continue
+		}
+		emit(dict[string(w)])
+		dict[string(wc)] = next
+		next++
+		w = []byte{c}
+	}
+	if len(w) > 0 {
+		emit(dict[string(w)])
+	}
+	write(lzwEOD)
+	if nbits > 0 {
+		out.WriteByte(byte(bits << (8 - nbits)))
+	}
+	return out.Bytes(), codes
+}
+
+func TestDecodeLZWExample(t *testing.T) {
+	// 7.4.4.2 Example of LZW Encoding
+	b := []byte{0x80, 0x0b, 0x60, 0x50, 0x22, 0x0c, 0x0c, 0x85, 0x01}
+	got, err := decodeLZW(b, 1, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "-----A---B"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDecodeLZWCodeWidth(t *testing.T) {
+	// few distinct bytes make codes for about 2000 entries which cross 511 and 1023
+	rnd := rand.New(rand.NewSource(1))
+	in := make([]byte, 8000)
+	for i := range in {
+		in[i] = "abcd"[rnd.Intn(4)]
+	}
+
+	for _, earlyChange := range []int{0, 1} {
+		enc, codes := encodeLZW(in, earlyChange)
+		if codes < 1100 || codes > 4000 {
+			t.Fatalf("EarlyChange %d: %d codes do not cross the 1023 boundary within the table", earlyChange, codes)
+		}
+		got, err := decodeLZW(enc, earlyChange, -1)
+		if err != nil {
+			t.Fatalf("EarlyChange %d: %v", earlyChange, err)
+		}
+		if !bytes.Equal(got, in) {
+			t.Errorf("EarlyChange %d: decoded data differs", earlyChange)
+		}
+
+		// the other early change must not decode it beyond the first boundary
+		if wrong, err := decodeLZW(enc, 1-earlyChange, -1); err == nil && bytes.Equal(wrong, in) {
+			t.Errorf("EarlyChange %d: decoded with the other early change", earlyChange)
+		}
+	}
+
+	// compress/lzw writes the variant without early change as GIF does
+	var ref bytes.Buffer
+	w := lzw.NewWriter(&ref, lzw.MSB, 8)
+	w.Write(in)
+	w.Close()
+	if enc, _ := encodeLZW(in, 0); !bytes.Equal(enc, ref.Bytes()) {
+		t.Error("the encoder without early change differs from compress/lzw")
+	}
+	got, err := decodeLZW(ref.Bytes(), 0, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, in) {
+		t.Error("data from compress/lzw decoded differently")
+	}
+}
+
+func TestLZWDecodeEarlyChangeParms(t *testing.T) {
+	in := bytes.Repeat([]byte("abcabcabd"), 300)
+	for _, earlyChange := range []int{0, 1} {
+		enc, _ := encodeLZW(in, earlyChange)
+		dict := PDFDict{"Filter": PDFName("LZWDecode")}
+		if earlyChange == 0 {
+			dict["DecodeParms"] = PDFDict{"EarlyChange": PDFInt(0)}
+		}
+		got, err := DecodeStream(dict, enc)
+		if err != nil {
+			t.Fatalf("EarlyChange %d: %v", earlyChange, err)
+		}
+		if !bytes.Equal(got, in) {
+			t.Errorf("EarlyChange %d: decoded data differs", earlyChange)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package pdf
+
+// 7.4.5 RunLengthDecode Filter
+// Data read so far is returned if the data ends without EOD.
+func runLengthDecode(b []byte, _ PDFDict, _ int64) ([]byte, error) {
+	var out []byte
+	for i := 0; i < len(b); {
+		length := int(b[i])
+		i++
+
+		switch {
+		case length == 128:
+			// EOD
+			return out, nil
+		case length < 128:
+			// copy the next length+1 bytes literally
+			end := i + length + 1
+			if end > len(b) {
+				end = len(b)
+			}
+			out = append(out, b[i:end]...)
+			i = end
+		default:
+			// repeat the next byte 257-length times
+			if i >= len(b) {
+				return out, nil
+			}
+			for n := 0; n < 257-length; n++ {
+				out = append(out, b[i])
+			}
+			i++
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,72 @@
+package pdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestApplyPredictor(t *testing.T) {
+	for _, tc := range []struct {
+		name                          string
+		predictor, colors, bpc, width int
+		in, want                      []byte
+	}{
+		{name: "none", predictor: 1, colors: 1, bpc: 8, width: 3, in: []byte{1, 2, 3}, want: []byte{1, 2, 3}},
+
+		// 2 rows of 10 20 30 and 15 25 40
+		{name: "TIFF", predictor: 2, colors: 1, bpc: 8, width: 3,
+			in: []byte{10, 10, 10, 15, 10, 15}, want: []byte{10, 20, 30, 15, 25, 40}},
+		{name: "TIFF RGB", predictor: 2, colors: 3, bpc: 8, width: 2,
+			in: []byte{1, 2, 3, 3, 4, 5}, want: []byte{1, 2, 3, 4, 6, 8}},
+		{name: "TIFF 16 bits", predictor: 2, colors: 1, bpc: 16, width: 2,
+			in: []byte{0x01, 0x00, 0x00, 0xff}, want: []byte{0x01, 0x00, 0x01, 0xff}},
+		{name: "TIFF 4 bits", predictor: 2, colors: 1, bpc: 4, width: 4,
+			in: []byte{0x11, 0x11}, want: []byte{0x12, 0x34}},
+		{name: "TIFF 1 bit", predictor: 2, colors: 1, bpc: 1, width: 8,
+			in: []byte{0x80}, want: []byte{0xff}},
+
+		{name: "PNG None", predictor: 10, colors: 1, bpc: 8, width: 3,
+			in: []byte{0, 10, 20, 30, 0, 15, 25, 40}, want: []byte{10, 20, 30, 15, 25, 40}},
+		{name: "PNG Sub", predictor: 11, colors: 1, bpc: 8, width: 3,
+			in: []byte{1, 10, 10, 10, 1, 15, 10, 15}, want: []byte{10, 20, 30, 15, 25, 40}},
+		{name: "PNG Up", predictor: 12, colors: 1, bpc: 8, width: 3,
+			in: []byte{2, 10, 20, 30, 2, 5, 5, 10}, want: []byte{10, 20, 30, 15, 25, 40}},
+		{name: "PNG Average", predictor: 13, colors: 1, bpc: 8, width: 3,
+			in: []byte{3, 10, 15, 20, 3, 10, 8, 13}, want: []byte{10, 20, 30, 15, 25, 40}},
+		{name: "PNG Paeth", predictor: 14, colors: 1, bpc: 8, width: 3,
+			in: []byte{4, 10, 10, 10, 4, 5, 5, 10}, want: []byte{10, 20, 30, 15, 25, 40}},
+		// the filter type of each row is used whatever the predictor from 10 to 15 is
+		{name: "PNG optimum", predictor: 15, colors: 1, bpc: 8, width: 3,
+			in: []byte{1, 10, 10, 10, 2, 5, 5, 10}, want: []byte{10, 20, 30, 15, 25, 40}},
+		{name: "PNG Sub overflow", predictor: 11, colors: 1, bpc: 8, width: 2,
+			in: []byte{1, 200, 100}, want: []byte{200, 44}},
+		{name: "PNG Sub RGB", predictor: 11, colors: 3, bpc: 8, width: 2,
+			in: []byte{1, 1, 2, 3, 3, 4, 5}, want: []byte{1, 2, 3, 4, 6, 8}},
+	} {
+		got, err := ApplyPredictor(tc.in, tc.predictor, tc.colors, tc.bpc, tc.width)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if !bytes.Equal(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestApplyPredictorError(t *testing.T) {
+	for _, tc := range []struct {
+		name                          string
+		predictor, colors, bpc, width int
+		in                            []byte
+	}{
+		{name: "unknown predictor", predictor: 3, colors: 1, bpc: 8, width: 1, in: []byte{1}},
+		{name: "bits per component", predictor: 2, colors: 1, bpc: 3, width: 1, in: []byte{1}},
+		{name: "columns", predictor: 12, colors: 1, bpc: 8, width: 0, in: []byte{0, 1}},
+		{name: "PNG filter type", predictor: 15, colors: 1, bpc: 8, width: 1, in: []byte{5, 1}},
+	} {
+		if got, err := ApplyPredictor(tc.in, tc.predictor, tc.colors, tc.bpc, tc.width); err == nil {
+			t.Errorf("%s: expected an error but got %v", tc.name, got)
+		}
+	}
+}
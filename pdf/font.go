@@ -0,0 +1,250 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PageFonts returns font dictionaries in /Resources /Font of page keyed by the resource name.
+// page must be one returned by Pages so that inherited /Resources is available.
+func (d *Document) PageFonts(page PDFDict) (map[string]PDFDict, error) {
+	resources, err := d.resolveDict(page["Resources"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /Resources: %w", err)
+	}
+	fontRes, err := d.resolveDict(resources["Font"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /Font: %w", err)
+	}
+
+	fonts := make(map[string]PDFDict, len(fontRes))
+	for name, obj := range fontRes {
+		font, err := d.resolveDict(obj)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve font /%s: %w", name, err)
+		}
+		fonts[name] = font
+	}
+	return fonts, nil
+}
+
+// DescendantFont returns the CIDFont of a Type0 font.
+// 9.7.6 Type 0 Font Dictionaries
+// /DescendantFonts is a one-element array of the CIDFont dictionary.
+func (d *Document) DescendantFont(font PDFDict) (PDFDict, error) {
+	if subtype, _ := font["Subtype"].(PDFName); subtype != "Type0" {
+		return nil, fmt.Errorf("font must be Type0 but got %q", string(subtype))
+	}
+
+	obj, err := d.Resolve(font["DescendantFonts"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /DescendantFonts: %w", err)
+	}
+	descendants, ok := obj.(PDFArray)
+	if !ok || len(descendants) == 0 {
+		return nil, errors.New("Type0 font must have /DescendantFonts")
+	}
+
+	descendant, err := d.resolveDict(descendants[0])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve the descendant font: %w", err)
+	}
+	return descendant, nil
+}
+
+// ErrFontNotEmbedded is returned by ExtractFont when the font has no embedded font program.
+var ErrFontNotEmbedded = errors.New("font is not embedded")
+
+// ExtractFont returns /BaseFont and the decoded font program embedded in the font descriptor of font
+// with the file extension for the format. The descriptor of a Type0 font is in its descendant font.
+// A Type 1 font program is converted to PFB when /Length1 and /Length2 split it into the clear-text and
+// the encrypted portion. Otherwise it is returned as-is with .t1.
+// 9.9 Embedded Font Programs
+func (d *Document) ExtractFont(font PDFDict) (name string, data []byte, ext string, err error) {
+	baseFont, _ := font["BaseFont"].(PDFName)
+	name = string(baseFont)
+
+	if subtype, _ := font["Subtype"].(PDFName); subtype == "Type0" {
+		font, err = d.DescendantFont(font)
+		if err != nil {
+			return name, nil, "", err
+		}
+	}
+
+	descriptor, err := d.resolveDict(font["FontDescriptor"])
+	if err != nil {
+		return name, nil, "", fmt.Errorf("unable to resolve /FontDescriptor: %w", err)
+	}
+
+	// Table 126 Embedded font organization for various font types
+	for _, key := range []string{"FontFile", "FontFile2", "FontFile3"} {
+		ref, ok := descriptor[key].(PDFRef)
+		if !ok {
+			continue
+		}
+		dict, b, err := d.readStream(ref)
+		if err != nil {
+			return name, nil, "", fmt.Errorf("unable to read /%s: %w", key, err)
+		}
+
+		switch key {
+		case "FontFile":
+			length1, ok1 := dict["Length1"].(PDFInt)
+			length2, ok2 := dict["Length2"].(PDFInt)
+			if ok1 && ok2 && length1 >= 0 && length2 >= 0 && int64(length1)+int64(length2) <= int64(len(b)) {
+				return name, type1ToPFB(b, int(length1), int(length2)), ".pfb", nil
+			}
+			return name, b, ".t1", nil
+		case "FontFile2":
+			return name, b, ".ttf", nil
+		default:
+			if subtype, _ := dict["Subtype"].(PDFName); subtype == "OpenType" {
+				return name, b, ".otf", nil
+			}
+			// Type1C or CIDFontType0C
+			return name, b, ".cff", nil
+		}
+	}
+	return name, nil, "", ErrFontNotEmbedded
+}
+
+// type1ToPFB wraps the clear-text, the binary and the trailing portions of a Type 1 font program
+// into PFB segments, each of which has a marker, a type and the length in little endian.
+func type1ToPFB(b []byte, length1, length2 int) []byte {
+	var pfb []byte
+	segment := func(typ byte, data []byte) {
+		n := len(data)
+		pfb = append(pfb, 0x80, typ, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+		pfb = append(pfb, data...)
+	}
+	segment(1, b[:length1])
+	segment(2, b[length1:length1+length2])
+	if rest := b[length1+length2:]; len(rest) > 0 {
+		segment(1, rest)
+	}
+	return append(pfb, 0x80, 3)
+}
+
+// FontWidths returns the widths of glyphs in 1/1000 of text space units keyed by the character code
+// of a simple font or by the CID of a Type0 font. Glyphs which are not in the map have the width of FontDefaultWidth.
+// 9.6.2 /FirstChar, /LastChar and /Widths of a simple font
+// 9.7.4.3 /W of a CIDFont
+func (d *Document) FontWidths(font PDFDict) (map[int]float64, error) {
+	if subtype, _ := font["Subtype"].(PDFName); subtype == "Type0" {
+		descendant, err := d.DescendantFont(font)
+		if err != nil {
+			return nil, err
+		}
+		return d.cidFontWidths(descendant)
+	}
+
+	widths := map[int]float64{}
+	if _, ok := font["Widths"]; !ok {
+		// the standard 14 fonts may omit /Widths
+		return widths, nil
+	}
+
+	first, err := d.Resolve(font["FirstChar"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /FirstChar: %w", err)
+	}
+	firstChar, ok := first.(PDFInt)
+	if !ok {
+		return nil, fmt.Errorf("/FirstChar must be an integer but got %T", first)
+	}
+	arr, err := d.resolveArray(font["Widths"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /Widths: %w", err)
+	}
+
+	for i, obj := range arr {
+		w, err := d.Resolve(obj)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve /Widths: %w", err)
+		}
+		if width, ok := toFloat(w); ok {
+			widths[int(firstChar)+i] = width
+		}
+	}
+	return widths, nil
+}
+
+// maxCIDRange limits the range of c_first c_last w in /W.
+const maxCIDRange = 0x10000
+
+func (d *Document) cidFontWidths(font PDFDict) (map[int]float64, error) {
+	widths := map[int]float64{}
+	if _, ok := font["W"]; !ok {
+		return widths, nil
+	}
+	arr, err := d.resolveArray(font["W"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /W: %w", err)
+	}
+
+	// c [w1 w2 ... wn] or c_first c_last w
+	for i := 0; i < len(arr); {
+		c, ok := arr[i].(PDFInt)
+		if !ok || i+1 >= len(arr) {
+			return nil, fmt.Errorf("/W has an invalid element at %d", i)
+		}
+
+		next, err := d.Resolve(arr[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve /W: %w", err)
+		}
+		if ws, ok := next.(PDFArray); ok {
+			for j, obj := range ws {
+				if w, ok := toFloat(obj); ok {
+					widths[int(c)+j] = w
+				}
+			}
+			i += 2
+			continue
+		}
+
+		last, ok := next.(PDFInt)
+		if !ok || i+2 >= len(arr) || last < c || last-c >= maxCIDRange {
+			return nil, fmt.Errorf("/W has an invalid range at %d", i)
+		}
+		w, ok := toFloat(arr[i+2])
+		if !ok {
+			return nil, fmt.Errorf("/W has an invalid width at %d", i+2)
+		}
+		for cid := int(c); cid <= int(last); cid++ {
+			widths[cid] = w
+		}
+		i += 3
+	}
+	return widths, nil
+}
+
+// FontDefaultWidth returns the width of glyphs which are not in FontWidths.
+// It is /MissingWidth of the font descriptor of a simple font (0 by default) or /DW of the CIDFont (1000 by default).
+func (d *Document) FontDefaultWidth(font PDFDict) (float64, error) {
+	if subtype, _ := font["Subtype"].(PDFName); subtype == "Type0" {
+		descendant, err := d.DescendantFont(font)
+		if err != nil {
+			return 0, err
+		}
+		dw, err := d.Resolve(descendant["DW"])
+		if err != nil {
+			return 0, fmt.Errorf("unable to resolve /DW: %w", err)
+		}
+		if w, ok := toFloat(dw); ok {
+			return w, nil
+		}
+		return 1000, nil
+	}
+
+	descriptor, err := d.resolveDict(font["FontDescriptor"])
+	if err != nil {
+		return 0, fmt.Errorf("unable to resolve /FontDescriptor: %w", err)
+	}
+	mw, err := d.Resolve(descriptor["MissingWidth"])
+	if err != nil {
+		return 0, fmt.Errorf("unable to resolve /MissingWidth: %w", err)
+	}
+	w, _ := toFloat(mw)
+	return w, nil
+}
@@ -0,0 +1,295 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormField is a terminal field of an interactive form, that is, a field without child fields.
+// 12.7.3 Field Dictionaries
+type FormField struct {
+	// Name is the fully qualified name which is the partial names /T from the root joined by periods
+	Name string
+	// Type is /FT such as Btn, Tx, Ch and Sig
+	Type PDFName
+	// Value is /V resolved or nil
+	Value PDFObject
+	// Flags is /Ff
+	Flags int
+
+	// Ref is the field dictionary or the zero PDFRef when it is a direct object
+	Ref  PDFRef
+	Dict PDFDict
+}
+
+// FormFields returns the terminal fields in /Fields of /AcroForm in the catalog in the order of the field tree.
+// /FT, /Ff and /V are inherited from the parent as in Table 220.
+// It returns nil without an error when the document has no interactive form.
+// 12.7.2 Interactive Form Dictionary
+func (d *Document) FormFields() ([]FormField, error) {
+	catalog, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := catalog["AcroForm"]; !ok {
+		return nil, nil
+	}
+	acroForm, err := d.resolveDict(catalog["AcroForm"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /AcroForm: %w", err)
+	}
+	obj, err := d.Resolve(acroForm["Fields"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /Fields: %w", err)
+	}
+	fields, _ := obj.(PDFArray)
+
+	var result []FormField
+	visited := map[PDFRef]bool{}
+	for _, field := range fields {
+		if err := d.readFormField(&result, field, FormField{}, visited, 1); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// readFormField appends the terminal fields of obj to result. parent has the name and the inherited entries.
+func (d *Document) readFormField(result *[]FormField, obj PDFObject, parent FormField, visited map[PDFRef]bool, depth int) error {
+	if err := d.checkDepth(depth, obj); err != nil {
+		return err
+	}
+	ref, _ := obj.(PDFRef)
+	if ref != (PDFRef{}) {
+		if visited[ref] {
+			return fmt.Errorf("cyclic field at %d %d R", ref.Number, ref.Generation)
+		}
+		visited[ref] = true
+	}
+	dict, err := d.resolveDict(obj)
+	if err != nil {
+		return fmt.Errorf("unable to resolve a field: %w", err)
+	}
+
+	field := FormField{Name: parent.Name, Type: parent.Type, Value: parent.Value, Flags: parent.Flags, Ref: ref, Dict: dict}
+
+	// Table 220 Entries common to all field dictionaries
+	if obj, err := d.Resolve(dict["T"]); err == nil {
+		if s, ok := obj.(PDFString); ok {
+			field.Name = joinFieldName(parent.Name, DecodeTextString(s))
+		}
+	}
+	if ft, ok := dict["FT"].(PDFName); ok {
+		field.Type = ft
+	}
+	if obj, err := d.Resolve(dict["Ff"]); err == nil {
+		if ff, ok := obj.(PDFInt); ok {
+			field.Flags = int(ff)
+		}
+	}
+	if v, ok := dict["V"]; ok {
+		resolved, err := d.Resolve(v)
+		if err != nil {
+			return fmt.Errorf("unable to resolve /V of field %q: %w", field.Name, err)
+		}
+		field.Value = resolved
+	}
+
+	kids, err := d.Resolve(dict["Kids"])
+	if err != nil {
+		return fmt.Errorf("unable to resolve /Kids of field %q: %w", field.Name, err)
+	}
+	arr, _ := kids.(PDFArray)
+
+	// 12.7.3.1 a kid without /T is a widget annotation of the field unless it has its own kids
+	var children []PDFObject
+	for _, kid := range arr {
+		kidDict, err := d.resolveDict(kid)
+		if err != nil {
+			return fmt.Errorf("unable to resolve a kid of field %q: %w", field.Name, err)
+		}
+		_, hasName := kidDict["T"]
+		_, hasKids := kidDict["Kids"]
+		if hasName || hasKids {
+			children = append(children, kid)
+		}
+	}
+	if len(children) == 0 {
+		*result = append(*result, field)
+		return nil
+	}
+	for _, kid := range children {
+		if err := d.readFormField(result, kid, field, visited, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinFieldName returns the fully qualified name of a partial name under parent.
+// 12.7.3.2 Field Names
+func joinFieldName(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// Table 226 Field flags specific to button fields
+const fieldFlagPushbutton = 1 << 16
+
+// FormValues returns the value of each field with a value as text by the fully qualified name.
+// A checkbox or a radio button is the name of the state such as Yes and Off, or the text in /Opt for the state.
+// The export value of a choice is the text shown for it in /Opt and multiple selections are joined by ", ".
+// Push buttons and signature fields are not included. A field without /V has an empty value.
+// 12.7.4 Field Types
+func (d *Document) FormValues() (map[string]string, error) {
+	fields, err := d.FormFields()
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	for _, field := range fields {
+		var value string
+		switch field.Type {
+		case "Tx":
+			v, err := d.textFieldValue(field)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read the value of field %q: %w", field.Name, err)
+			}
+			value = v
+		case "Btn":
+			if field.Flags&fieldFlagPushbutton != 0 {
+				continue
+			}
+			if state, ok := field.Value.(PDFName); ok {
+				value = d.buttonStateText(field, state)
+			}
+		case "Ch":
+			value = d.choiceText(field)
+		default:
+			continue
+		}
+		values[field.Name] = value
+	}
+	return values, nil
+}
+
+// textFieldValue returns /V of a text field which is a text string or a text stream.
+// 12.7.4.3 Text Fields
+func (d *Document) textFieldValue(field FormField) (string, error) {
+	switch v := field.Value.(type) {
+	case PDFString:
+		return DecodeTextString(v), nil
+	case PDFStream:
+		ref, ok := field.Dict["V"].(PDFRef)
+		if !ok {
+			return "", nil
+		}
+		_, b, err := d.readStream(ref)
+		if err != nil {
+			return "", err
+		}
+		return DecodeTextString(PDFString(b)), nil
+	}
+	return "", nil
+}
+
+// buttonStateText returns the text of the appearance state of a checkbox or a radio button.
+// 12.7.4.2.3 /Opt has the export value of each widget in the order of /Kids
+// and the state name of a widget is then typically its index.
+func (d *Document) buttonStateText(field FormField, state PDFName) string {
+	if state == "Off" {
+		return string(state)
+	}
+	obj, err := d.Resolve(field.Dict["Opt"])
+	if err != nil {
+		return string(state)
+	}
+	opt, _ := obj.(PDFArray)
+	if len(opt) == 0 {
+		return string(state)
+	}
+
+	widgets := PDFArray{field.Dict}
+	if obj, err := d.Resolve(field.Dict["Kids"]); err == nil {
+		if kids, ok := obj.(PDFArray); ok {
+			widgets = kids
+		}
+	}
+	for i, widget := range widgets {
+		if i >= len(opt) {
+			break
+		}
+		dict, err := d.resolveDict(widget)
+		if err != nil {
+			continue
+		}
+		ap, err := d.resolveDict(dict["AP"])
+		if err != nil {
+			continue
+		}
+		normal, err := d.resolveDict(ap["N"])
+		if err != nil {
+			continue
+		}
+		if _, ok := normal[string(state)]; !ok {
+			continue
+		}
+		if obj, err := d.Resolve(opt[i]); err == nil {
+			if s, ok := obj.(PDFString); ok {
+				return DecodeTextString(s)
+			}
+		}
+	}
+	return string(state)
+}
+
+// choiceText returns the selected options of a choice field.
+// 12.7.4.4 an element of /Opt is a text string or an array of the export value and the text to show.
+func (d *Document) choiceText(field FormField) string {
+	var selected []PDFObject
+	switch v := field.Value.(type) {
+	case PDFString:
+		selected = []PDFObject{v}
+	case PDFArray:
+		selected = v
+	}
+
+	shown := map[string]string{}
+	if obj, err := d.Resolve(field.Dict["Opt"]); err == nil {
+		opt, _ := obj.(PDFArray)
+		for _, elem := range opt {
+			pair, err := d.Resolve(elem)
+			if err != nil {
+				continue
+			}
+			if arr, ok := pair.(PDFArray); ok && len(arr) == 2 {
+				export, ok1 := arr[0].(PDFString)
+				text, ok2 := arr[1].(PDFString)
+				if ok1 && ok2 {
+					shown[string(export)] = DecodeTextString(text)
+				}
+			}
+		}
+	}
+
+	texts := make([]string, 0, len(selected))
+	for _, obj := range selected {
+		obj, err := d.Resolve(obj)
+		if err != nil {
+			continue
+		}
+		s, ok := obj.(PDFString)
+		if !ok {
+			continue
+		}
+		if text, ok := shown[string(s)]; ok {
+			texts = append(texts, text)
+			continue
+		}
+		texts = append(texts, DecodeTextString(s))
+	}
+	return strings.Join(texts, ", ")
+}
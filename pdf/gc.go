@@ -0,0 +1,150 @@
+package pdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteGC writes a new PDF file to w which has only the objects reachable from /Root and /Info.
+// Objects are renumbered sequentially from 1 with generation 0 in the order found from /Root
+// and are written as regular objects with a classic cross-reference table.
+// An encrypted document must be decrypted with Decrypt and is written without encryption.
+// A reference to a free or undefined object is replaced with null as it is the null object (7.3.10).
+// An error is returned if a reachable object cannot be read so that no reference is left dangling.
+func (d *Document) WriteGC(w io.Writer) error {
+	encrypted, _, err := d.IsEncrypted()
+	if err != nil {
+		return err
+	}
+	if encrypted && d.crypt == nil {
+		return ErrEncrypted
+	}
+
+	version, err := d.Version()
+	if err != nil {
+		return err
+	}
+
+	// the object numbers reached are not needed since object streams are not written
+	refs, _ := d.reachable([]string{"Root", "Info"}, false)
+
+	// read all objects first so that null objects are not numbered
+	objs := map[PDFRef]PDFObject{}
+	numbers := map[PDFRef]int64{}
+	var order []PDFRef
+	for _, ref := range refs {
+		obj, err := d.readObject(ref)
+		if err != nil {
+			return err
+		}
+		if _, ok := obj.(PDFNull); ok {
+			continue
+		}
+		objs[ref] = obj
+		order = append(order, ref)
+		numbers[ref] = int64(len(order))
+	}
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	// 7.5.2 a comment of binary characters follows the header
+	fmt.Fprintf(cw, "%%PDF-%s\n%%\xe2\xe3\xcf\xd3\n", version)
+
+	offsets := make([]int64, 0, len(order))
+	for _, ref := range order {
+		offsets = append(offsets, cw.n)
+		fmt.Fprintf(cw, "%d 0 obj\n", numbers[ref])
+
+		switch obj := renumberRefs(objs[ref], numbers).(type) {
+		case PDFStream:
+			ent, err := d.XrefEntry(ref.Number, ref.Generation)
+			if err != nil {
+				return err
+			}
+			// the body stays encoded but is decrypted
+			body, err := d.ReadStreamBody(ent, objs[ref].(PDFStream).Dict)
+			if err != nil {
+				return fmt.Errorf("unable to read %d %d R: %w", ref.Number, ref.Generation, err)
+			}
+			obj.Dict["Length"] = PDFInt(len(body))
+			fmt.Fprintf(cw, "%s\nstream\n", obj.Dict)
+			cw.Write(body)
+			fmt.Fprint(cw, "\nendstream")
+		default:
+			fmt.Fprint(cw, renderObject(obj))
+		}
+		fmt.Fprint(cw, "\nendobj\n")
+	}
+
+	// 7.5.4 each entry is 20 bytes long
+	xrefOffset := cw.n
+	fmt.Fprintf(cw, "xref\n0 %d\n", len(order)+1)
+	fmt.Fprint(cw, "0000000000 65535 f\r\n")
+	for _, offset := range offsets {
+		fmt.Fprintf(cw, "%010d 00000 n\r\n", offset)
+	}
+
+	// one entry per line
+	fmt.Fprintf(cw, "trailer\n<<\n/Size %d\n", len(order)+1)
+	for _, key := range []string{"Root", "Info"} {
+		if v, ok := d.trailerDict[key]; ok {
+			fmt.Fprintf(cw, "%s %s\n", PDFName(key), renderObject(renumberRefs(v, numbers)))
+		}
+	}
+	if id, ok := d.trailerDict["ID"]; ok {
+		fmt.Fprintf(cw, "/ID %s\n", renderObject(id))
+	}
+	fmt.Fprintf(cw, ">>\nstartxref\n%d\n%%%%EOF\n", xrefOffset)
+
+	if cw.err != nil {
+		return fmt.Errorf("unable to write: %w", cw.err)
+	}
+	return cw.w.Flush()
+}
+
+// renumberRefs returns a copy of obj whose references are replaced with the new numbers.
+// A reference without a new number is replaced with null.
+func renumberRefs(obj PDFObject, numbers map[PDFRef]int64) PDFObject {
+	switch obj := obj.(type) {
+	case PDFRef:
+		number, ok := numbers[obj]
+		if !ok {
+			return PDFNull{}
+		}
+		return PDFRef{Number: number}
+	case PDFArray:
+		arr := make(PDFArray, len(obj))
+		for i, elem := range obj {
+			arr[i] = renumberRefs(elem, numbers)
+		}
+		return arr
+	case PDFDict:
+		dict := make(PDFDict, len(obj))
+		for k, v := range obj {
+			dict[k] = renumberRefs(v, numbers)
+		}
+		return dict
+	case PDFStream:
+		return PDFStream{Dict: renumberRefs(obj.Dict, numbers).(PDFDict)}
+	}
+	return obj
+}
+
+// countingWriter counts bytes written to find offsets of objects.
+// The first error is kept and later writes are ignored.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(b []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(b)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
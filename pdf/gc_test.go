@@ -0,0 +1,198 @@
+package pdf
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteGC(t *testing.T) {
+	for _, file := range []string{"incr.pdf", "hybrid.pdf", "xrefstm.pdf"} {
+		t.Run(file, func(t *testing.T) {
+			b, err := os.ReadFile(filepath.Join("testdata", file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := openBytes(t, b)
+			orphans, err := d.Orphans()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(orphans) == 0 {
+				t.Fatal("the fixture has no orphan")
+			}
+
+			var out bytes.Buffer
+			if err := d.WriteGC(&out); err != nil {
+				t.Fatal(err)
+			}
+			gc, err := OpenReader(bytes.NewReader(out.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pages, err := d.Pages()
+			if err != nil {
+				t.Fatal(err)
+			}
+			gcPages, err := gc.Pages()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(gcPages) != len(pages) {
+				t.Errorf("got %d pages, want %d", len(gcPages), len(pages))
+			}
+
+			// the catalog is the same but the numbers of the references
+			catalog, err := d.Catalog()
+			if err != nil {
+				t.Fatal(err)
+			}
+			gcCatalog, err := gc.Catalog()
+			if err != nil {
+				t.Fatal(err)
+			}
+			compareRenumbered(t, "/Root", d, catalog, gc, gcCatalog, map[[2]PDFRef]bool{})
+
+			// no orphan is left and every object in the output is none of the orphans
+			if left, err := gc.Orphans(); err != nil || len(left) != 0 {
+				t.Errorf("got orphans %v, %v", left, err)
+			}
+			var written []PDFObject
+			for _, ent := range gc.XrefEntries() {
+				if !ent.InUse {
+					continue
+				}
+				if ent.Generation != 0 || ent.Compressed {
+					t.Errorf("got %+v, want an uncompressed entry of generation 0", ent)
+				}
+				obj, err := gc.GetObject(ent.Number, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				written = append(written, obj)
+			}
+			for _, number := range orphans {
+				orphan, err := d.GetObject(number, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, obj := range written {
+					if reflect.DeepEqual(obj, orphan) {
+						t.Errorf("the orphan %d 0 R is written: %v", number, orphan)
+					}
+				}
+			}
+		})
+	}
+}
+
+// compareRenumbered compares obj of d with gcObj of gc where references may have different numbers.
+func compareRenumbered(t *testing.T, path string, d *Document, obj PDFObject, gc *Document, gcObj PDFObject, seen map[[2]PDFRef]bool) {
+	t.Helper()
+	if ref, ok := obj.(PDFRef); ok {
+		gcRef, ok := gcObj.(PDFRef)
+		if !ok {
+			t.Errorf("%s: got %v, want a reference", path, gcObj)
+			return
+		}
+		if seen[[2]PDFRef{ref, gcRef}] {
+			return
+		}
+		seen[[2]PDFRef{ref, gcRef}] = true
+
+		var err error
+		if obj, err = d.Resolve(ref); err != nil {
+			t.Fatal(err)
+		}
+		if gcObj, err = gc.Resolve(gcRef); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	switch obj := obj.(type) {
+	case PDFDict:
+		gcDict, ok := gcObj.(PDFDict)
+		if !ok || len(gcDict) != len(obj) {
+			t.Errorf("%s: got %v, want %v", path, gcObj, obj)
+			return
+		}
+		for k, v := range obj {
+			compareRenumbered(t, path+"/"+k, d, v, gc, gcDict[k], seen)
+		}
+	case PDFArray:
+		gcArr, ok := gcObj.(PDFArray)
+		if !ok || len(gcArr) != len(obj) {
+			t.Errorf("%s: got %v, want %v", path, gcObj, obj)
+			return
+		}
+		for i := range obj {
+			compareRenumbered(t, path, d, obj[i], gc, gcArr[i], seen)
+		}
+	case PDFStream:
+		gcStream, ok := gcObj.(PDFStream)
+		if !ok {
+			t.Errorf("%s: got %v, want a stream", path, gcObj)
+			return
+		}
+		// /Length is rewritten as a direct object
+		dict, gcDict := PDFDict{}, PDFDict{}
+		for k, v := range obj.Dict {
+			if k != "Length" {
+				dict[k] = v
+			}
+		}
+		for k, v := range gcStream.Dict {
+			if k != "Length" {
+				gcDict[k] = v
+			}
+		}
+		compareRenumbered(t, path, d, dict, gc, gcDict, seen)
+	default:
+		if !reflect.DeepEqual(gcObj, obj) {
+			t.Errorf("%s: got %v, want %v", path, gcObj, obj)
+		}
+	}
+}
+
+func TestWriteGCReferences(t *testing.T) {
+	// 9 0 R is undefined and is the null object
+	d := openBytes(t, buildXrefStreamPDF(map[int64]string{
+		1: "<< /Type /Catalog /Pages 2 0 R /Extra 9 0 R >>",
+		2: "<< /Type /Pages /Kids [] /Count 0 >>",
+		3: "(orphan)",
+	}, nil))
+	var out bytes.Buffer
+	if err := d.WriteGC(&out); err != nil {
+		t.Fatal(err)
+	}
+	gc, err := OpenReader(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog, err := gc.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if catalog["Extra"] != (PDFNull{}) || catalog["Pages"] != (PDFRef{Number: 2}) {
+		t.Errorf("got %v", catalog)
+	}
+	if got := gc.TrailerDict()["Size"]; got != PDFInt(3) {
+		t.Errorf("got /Size %v, want 3", got)
+	}
+	if bytes.Contains(out.Bytes(), []byte("(orphan)")) {
+		t.Error("the orphan is written")
+	}
+
+	// a reachable object which cannot be read is an error rather than a dangling reference
+	d = openBytes(t, buildXrefStreamPDF(map[int64]string{
+		1: "<< /Type /Catalog /Pages 2 0 R >>",
+		2: "<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		3: "<< /Type /Page /Parent 2 0 R /Contents (unterminated >>",
+	}, nil))
+	if err := d.WriteGC(&bytes.Buffer{}); err == nil {
+		t.Error("expected an error for the unreadable 3 0 R")
+	}
+}
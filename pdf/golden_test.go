@@ -0,0 +1,128 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenXref is what the cross-reference functions return for a file in testdata.
+type goldenXref struct {
+	StartXref int64
+	Size      int64
+	Trailer   PDFDict
+	// Section is the newest cross-reference section returned by ListXrefEntries
+	Section []XrefEntry
+	// Entries has the entries of all revisions merged by Open
+	Entries []XrefEntry
+}
+
+// TestGolden opens each PDF in testdata and compares the cross-reference entries with <name>.xref.golden.json
+// and the objects dumped by DumpJSON with <name>.golden.json. Run "go test -update" after an intended change.
+func TestGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no PDF in testdata")
+	}
+
+	for _, path := range files {
+		path := path
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ra, size := bytes.NewReader(b), int64(len(b))
+
+			tr, err := ReadTrailer(ra, size)
+			if err != nil {
+				t.Fatalf("unable to read the trailer: %v", err)
+			}
+			section, err := tr.ListXrefEntries()
+			if err != nil {
+				t.Fatalf("unable to list xref entries: %v", err)
+			}
+
+			d, err := Open(ra, size)
+			if err != nil {
+				t.Fatalf("unable to open: %v", err)
+			}
+			entries := d.XrefEntries()
+			for _, ent := range entries {
+				if !ent.InUse {
+					continue
+				}
+				found, err := d.XrefEntry(ent.Number, ent.Generation)
+				if err != nil {
+					t.Errorf("unable to find %d %d R: %v", ent.Number, ent.Generation, err)
+				} else if found != ent {
+					t.Errorf("XrefEntry(%d, %d) = %+v, want %+v", ent.Number, ent.Generation, found, ent)
+				}
+			}
+
+			xref, err := json.MarshalIndent(goldenXref{
+				StartXref: tr.StartXref,
+				Size:      tr.Size,
+				Trailer:   tr.Dict,
+				Section:   section,
+				Entries:   entries,
+			}, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			compareGolden(t, strings.TrimSuffix(path, ".pdf")+".xref.golden.json", append(xref, '\n'))
+
+			var dump bytes.Buffer
+			if err := d.DumpJSON(&dump, false); err != nil {
+				t.Fatal(err)
+			}
+			compareGolden(t, strings.TrimSuffix(path, ".pdf")+".golden.json", dump.Bytes())
+		})
+	}
+}
+
+// compareGolden compares got with the golden file at path or rewrites the file with -update.
+func compareGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read the golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s does not match:\n%s", path, firstDiff(string(want), string(got)))
+	}
+}
+
+// firstDiff returns the first line which differs between want and got.
+func firstDiff(want, got string) string {
+	wl, gl := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wl) || i < len(gl); i++ {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n- %s\n+ %s", i+1, w, g)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package pdf
+
+// graphicsState is the part of the graphics state which the interpreters need.
+// 8.4 Graphics State
+type graphicsState struct {
+	// ctm maps user space to the default user space
+	ctm  Matrix
+	text textState
+}
+
+func newGraphicsState() graphicsState {
+	return graphicsState{ctm: IdentityMatrix, text: textState{scale: 1}}
+}
+
+// graphicsStack interprets the operators which save, restore and transform the graphics state.
+// 8.4.2 Graphics State Stack
+type graphicsStack struct {
+	graphicsState
+	saved []graphicsState
+}
+
+func newGraphicsStack() *graphicsStack {
+	return &graphicsStack{graphicsState: newGraphicsState()}
+}
+
+// apply interprets q, Q and cm and returns true if op is one of them.
+// Q without q in the same content stream is ignored.
+func (gs *graphicsStack) apply(op ContentOp) bool {
+	switch op.Operator {
+	case "q":
+		gs.saved = append(gs.saved, gs.graphicsState)
+	case "Q":
+		if n := len(gs.saved); n > 0 {
+			gs.graphicsState = gs.saved[n-1]
+			gs.saved = gs.saved[:n-1]
+		}
+	case "cm":
+		// 8.4.4 the new CTM is the matrix followed by the current one
+		if m, ok := matrixOf(op.Operands); ok {
+			gs.ctm = m.Multiply(gs.ctm)
+		}
+	default:
+		return false
+	}
+	return true
+}
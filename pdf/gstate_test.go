@@ -0,0 +1,117 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestGraphicsStack(t *testing.T) {
+	ops, err := ParseContentStream([]byte(`
+1 0 0 1 10 0 cm
+q
+  2 0 0 2 0 0 cm
+  q
+    0 1 -1 0 0 0 cm
+  Q
+  1 0 0 1 5 5 cm
+Q
+Q
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the CTM after each operator
+	want := []Matrix{
+		{1, 0, 0, 1, 10, 0},
+		{1, 0, 0, 1, 10, 0},
+		// 8.4.4 the scale is applied before the translation already in the CTM
+		{2, 0, 0, 2, 10, 0},
+		{2, 0, 0, 2, 10, 0},
+		{0, 2, -2, 0, 10, 0},
+		{2, 0, 0, 2, 10, 0},
+		{2, 0, 0, 2, 20, 10},
+		{1, 0, 0, 1, 10, 0},
+		// an unbalanced Q is ignored
+		{1, 0, 0, 1, 10, 0},
+	}
+	if len(ops) != len(want) {
+		t.Fatalf("got %d operators, want %d", len(ops), len(want))
+	}
+	gs := newGraphicsStack()
+	for i, op := range ops {
+		if !gs.apply(op) {
+			t.Fatalf("%s is not applied", op.Operator)
+		}
+		if gs.ctm != want[i] {
+			t.Errorf("%d %s: got %v, want %v", i, op.Operator, gs.ctm, want[i])
+		}
+	}
+	if len(gs.saved) != 0 {
+		t.Errorf("got %d saved states", len(gs.saved))
+	}
+
+	if x, y := gs.ctm.Transform(1, 1); x != 11 || y != 1 {
+		t.Errorf("got (%v, %v), want (11, 1)", x, y)
+	}
+	if gs.apply(ContentOp{Operator: "Tj"}) {
+		t.Error("Tj is applied")
+	}
+}
+
+func TestPageTextRunsNestedGraphicsState(t *testing.T) {
+	content := `
+q 2 0 0 2 100 100 cm
+  BT /F1 10 Tf (outer) Tj ET
+  q 1 0 0 1 10 20 cm
+    BT (inner) Tj ET
+    q 0 1 -1 0 0 0 cm BT (rotated) Tj ET Q
+    BT /F1 5 Tf (smaller) Tj ET
+  Q
+  BT (restored) Tj ET
+Q
+BT /F1 12 Tf 5 5 Td (page) Tj ET
+Q
+BT (unbalanced) Tj ET
+`
+	d := openBytes(t, buildXrefStreamPDF(map[int64]string{
+		1: "<< /Type /Catalog /Pages 2 0 R >>",
+		2: "<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		3: "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R >>",
+		4: fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+	}, nil))
+	page, err := d.resolveDict(PDFRef{Number: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runs, err := d.PageTextRuns(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []TextRun{
+		{Text: "outer", X: 100, Y: 100, FontSize: 20},
+		// [1 0 0 1 10 20] × [2 0 0 2 100 100] = [2 0 0 2 120 140]
+		{Text: "inner", X: 120, Y: 140, FontSize: 20},
+		{Text: "rotated", X: 120, Y: 140, FontSize: 20},
+		{Text: "smaller", X: 120, Y: 140, FontSize: 10},
+		// Q restores the font size set before the inner q
+		{Text: "restored", X: 100, Y: 100, FontSize: 20},
+		{Text: "page", X: 5, Y: 5, FontSize: 12},
+		{Text: "unbalanced", X: 0, Y: 0, FontSize: 12},
+	}
+	if len(runs) != len(want) {
+		t.Fatalf("got %d runs, want %d: %+v", len(runs), len(want), runs)
+	}
+	for i := range want {
+		got := runs[i]
+		if got.Text != want[i].Text || !near(got.X, want[i].X) || !near(got.Y, want[i].Y) || !near(got.FontSize, want[i].FontSize) {
+			t.Errorf("got %+v, want %+v", got, want[i])
+		}
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
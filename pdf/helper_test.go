@@ -0,0 +1,152 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"testing"
+)
+
+// buildXrefStreamPDF returns a PDF with objs and a cross-reference stream which also has
+// the entries of compressed objects as the object stream number and the index. /Root is 1 0 R.
+func buildXrefStreamPDF(objs map[int64]string, compressed map[int64][2]int64) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.5\n")
+
+	var numbers []int64
+	for n := range objs {
+		numbers = append(numbers, n)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	size := int64(1)
+	for _, n := range numbers {
+		if n+1 > size {
+			size = n + 1
+		}
+	}
+	for n := range compressed {
+		if n+1 > size {
+			size = n + 1
+		}
+	}
+	xrefNum := size
+	size++
+
+	offsets := map[int64]int64{}
+	for _, n := range numbers {
+		offsets[n] = int64(buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", n, objs[n])
+	}
+	offsets[xrefNum] = int64(buf.Len())
+
+	// /W [1 4 2]
+	var rows []byte
+	for n := int64(0); n < size; n++ {
+		if c, ok := compressed[n]; ok {
+			rows = append(rows, 2, byte(c[0]>>24), byte(c[0]>>16), byte(c[0]>>8), byte(c[0]), byte(c[1]>>8), byte(c[1]))
+			continue
+		}
+		if off, ok := offsets[n]; ok {
+			rows = append(rows, 1, byte(off>>24), byte(off>>16), byte(off>>8), byte(off), 0, 0)
+			continue
+		}
+		rows = append(rows, 0, 0, 0, 0, 0, 0xff, 0xff)
+	}
+	fmt.Fprintf(&buf, "%d 0 obj\n<< /Type /XRef /Size %d /W [1 4 2] /Root 1 0 R /Length %d >>\nstream\n", xrefNum, size, len(rows))
+	buf.Write(rows)
+	fmt.Fprintf(&buf, "\nendstream\nendobj\nstartxref\n%d\n%%%%EOF\n", offsets[xrefNum])
+	return buf.Bytes()
+}
+
+// buildClassicPDF returns a PDF with a cross-reference table of n objects where 1 0 R is the catalog
+// and each of the others is a small dictionary.
+func buildClassicPDF(n int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, n+1)
+	for i := 1; i <= n; i++ {
+		offsets[i] = buf.Len()
+		if i == 1 {
+			fmt.Fprintf(&buf, "1 0 obj\n<< /Type /Catalog >>\nendobj\n")
+			continue
+		}
+		fmt.Fprintf(&buf, "%d 0 obj\n<< /Index %d /Name (object %d) >>\nendobj\n", i, i, i)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", n+1)
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offsets[i])
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", n+1, xref)
+	return buf.Bytes()
+}
+
+// openBytes opens the PDF in b.
+func openBytes(t testing.TB, b []byte) *Document {
+	t.Helper()
+	d, err := Open(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// checkClassicObjects checks that every object of buildClassicPDF(n) resolves in d.
+func checkClassicObjects(t *testing.T, d *Document, n int) {
+	t.Helper()
+	for i := 2; i <= n; i++ {
+		dict, err := d.GetDict(int64(i), 0)
+		if err != nil {
+			t.Errorf("%d 0 R: %v", i, err)
+			continue
+		}
+		if dict["Index"] != PDFInt(i) {
+			t.Errorf("%d 0 R: got %v", i, dict)
+		}
+	}
+	if _, err := d.Catalog(); err != nil {
+		t.Error(err)
+	}
+}
+
+// buildClassicPDFObjects returns a PDF with objs and a cross-reference table whose trailer is
+// /Size, /Root 1 0 R and extra. Each object is written as "N 0 obj\n<obj>\nendobj" unless it starts with "N 0 obj".
+func buildClassicPDFObjects(objs map[int64]string, extra string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	size := int64(1)
+	for n := range objs {
+		if n+1 > size {
+			size = n + 1
+		}
+	}
+
+	offsets := make([]int, size)
+	for n := int64(1); n < size; n++ {
+		obj, ok := objs[n]
+		if !ok {
+			continue
+		}
+		offsets[n] = buf.Len()
+		if header := fmt.Sprintf("%d 0 obj", n); len(obj) >= len(header) && obj[:len(header)] == header {
+			fmt.Fprintf(&buf, "%s\n", obj)
+			continue
+		}
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", n, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", size)
+	for n := int64(1); n < size; n++ {
+		if _, ok := objs[n]; !ok {
+			buf.WriteString("0000000000 00000 f \n")
+			continue
+		}
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offsets[n])
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R%s >>\nstartxref\n%d\n%%%%EOF\n", size, extra, xref)
+	return buf.Bytes()
+}
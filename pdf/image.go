@@ -0,0 +1,328 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"sort"
+)
+
+// ErrUnsupportedImage is returned when an image cannot be decoded into image.Image.
+var ErrUnsupportedImage = errors.New("unsupported image")
+
+// 8.9.5 Image Dictionaries
+type Image struct {
+	// Name is the key in /Resources /XObject
+	Name string
+	Ref  PDFRef
+	Dict PDFDict
+
+	Width            int
+	Height           int
+	BitsPerComponent int
+	// ColorSpace is the color space family such as DeviceRGB or Indexed. It is empty for an image mask.
+	ColorSpace PDFName
+	Filters    []PDFName
+
+	// 8.9.6.2 an image mask is a stencil painted with the current color
+	ImageMask bool
+	// SMask is the soft-mask image or the zero PDFRef
+	SMask PDFRef
+
+	d *Document
+}
+
+// PageImages returns image XObjects in /Resources /XObject of page sorted by the resource name.
+// page must be one returned by Pages so that inherited /Resources is available.
+// Images in form XObjects are not included.
+func (d *Document) PageImages(page PDFDict) ([]Image, error) {
+	resources, err := d.resolveDict(page["Resources"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /Resources: %w", err)
+	}
+	xobjects, err := d.resolveDict(resources["XObject"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /XObject: %w", err)
+	}
+
+	var images []Image
+	for name, obj := range xobjects {
+		ref, ok := obj.(PDFRef)
+		if !ok {
+			return nil, fmt.Errorf("XObject /%s must be an indirect reference but got %T", name, obj)
+		}
+		resolved, err := d.Resolve(ref)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve XObject /%s: %w", name, err)
+		}
+		stream, ok := resolved.(PDFStream)
+		if !ok {
+			return nil, fmt.Errorf("XObject /%s must be a stream but got %T", name, resolved)
+		}
+		if subtype, _ := stream.Dict["Subtype"].(PDFName); subtype != "Image" {
+			continue
+		}
+
+		img, err := d.image(name, ref, stream.Dict)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read image /%s: %w", name, err)
+		}
+		images = append(images, img)
+	}
+
+	sort.Slice(images, func(i, j int) bool { return images[i].Name < images[j].Name })
+	return images, nil
+}
+
+// Table 89 Additional Entries Specific to an Image Dictionary
+func (d *Document) image(name string, ref PDFRef, dict PDFDict) (Image, error) {
+	img := Image{Name: name, Ref: ref, Dict: dict, d: d}
+
+	for key, v := range map[string]*int{"Width": &img.Width, "Height": &img.Height, "BitsPerComponent": &img.BitsPerComponent} {
+		obj, err := d.Resolve(dict[key])
+		if err != nil {
+			return img, fmt.Errorf("unable to resolve /%s: %w", key, err)
+		}
+		if n, ok := obj.(PDFInt); ok {
+			*v = int(n)
+		}
+	}
+
+	if mask, _ := dict["ImageMask"].(PDFBool); mask {
+		img.ImageMask = true
+		img.BitsPerComponent = 1
+	} else {
+		cs, err := d.Resolve(dict["ColorSpace"])
+		if err != nil {
+			return img, fmt.Errorf("unable to resolve /ColorSpace: %w", err)
+		}
+		if arr, ok := cs.(PDFArray); ok && len(arr) > 0 {
+			cs = arr[0]
+		}
+		name, _ := cs.(PDFName)
+		img.ColorSpace = canonicalColorSpace(name)
+	}
+	img.SMask, _ = dict["SMask"].(PDFRef)
+
+	filters, _, err := streamFilters(dict)
+	if err != nil {
+		return img, err
+	}
+	img.Filters = filters
+	return img, nil
+}
+
+// IsJPEG returns true when the image is stored as JPEG, that is, the last filter is DCTDecode.
+func (img Image) IsJPEG() bool {
+	return len(img.Filters) > 0 && img.Filters[len(img.Filters)-1] == "DCTDecode"
+}
+
+// JPEG returns the JPEG file of the image. The filters before DCTDecode are decoded.
+func (img Image) JPEG() ([]byte, error) {
+	if !img.IsJPEG() {
+		return nil, errors.New("image is not DCTDecode")
+	}
+
+	ent, err := img.d.XrefEntry(img.Ref.Number, img.Ref.Generation)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := img.d.ReadStreamBody(ent, img.Dict)
+	if err != nil {
+		return nil, err
+	}
+
+	// decode with the filters other than the last DCTDecode
+	dict := PDFDict{}
+	n := len(img.Filters) - 1
+	if n > 0 {
+		filters := make(PDFArray, n)
+		for i, f := range img.Filters[:n] {
+			filters[i] = f
+		}
+		dict["Filter"] = filters
+		switch parms := img.Dict["DecodeParms"].(type) {
+		case PDFDict:
+			dict["DecodeParms"] = parms
+		case PDFArray:
+			if len(parms) > n {
+				dict["DecodeParms"] = parms[:n]
+			}
+		}
+	}
+	return DecodeStreamWithLimit(dict, raw, img.d.decodedSizeLimit())
+}
+
+// Decode decodes the samples of the image. DeviceGray and DeviceRGB with 8 bits per component,
+// and DeviceGray with 1 bit per component and image masks such as CCITTFaxDecode scans are supported.
+// JPEG is decoded by image/jpeg and CMYK JPEG is converted to RGB.
+// An image mask is black where it is painted. Masks and soft masks are not applied.
+func (img Image) Decode() (image.Image, error) {
+	if img.Width <= 0 || img.Height <= 0 {
+		return nil, fmt.Errorf("invalid image size %dx%d", img.Width, img.Height)
+	}
+
+	var components int
+	switch {
+	case img.ImageMask || (img.ColorSpace == "DeviceGray" && img.BitsPerComponent == 1):
+		return img.decodeMonochrome()
+	case img.IsJPEG():
+		return img.decodeJPEG()
+	case img.BitsPerComponent != 8:
+		return nil, fmt.Errorf("%w: %d bits per component", ErrUnsupportedImage, img.BitsPerComponent)
+	case img.ColorSpace == "DeviceGray":
+		components = 1
+	case img.ColorSpace == "DeviceRGB":
+		components = 3
+	default:
+		return nil, fmt.Errorf("%w: /%s", ErrUnsupportedImage, string(img.ColorSpace))
+	}
+
+	_, b, err := img.d.readStream(img.Ref)
+	if err != nil {
+		return nil, err
+	}
+	stride := img.Width * components
+	if len(b)/stride < img.Height {
+		return nil, fmt.Errorf("image data is too short: %d bytes for %dx%d", len(b), img.Width, img.Height)
+	}
+
+	rect := image.Rect(0, 0, img.Width, img.Height)
+	if components == 1 {
+		gray := image.NewGray(rect)
+		for y := 0; y < img.Height; y++ {
+			copy(gray.Pix[y*gray.Stride:], b[y*stride:(y+1)*stride])
+		}
+		return gray, nil
+	}
+
+	rgba := image.NewNRGBA(rect)
+	for y := 0; y < img.Height; y++ {
+		row := b[y*stride:]
+		for x := 0; x < img.Width; x++ {
+			rgba.SetNRGBA(x, y, color.NRGBA{R: row[x*3], G: row[x*3+1], B: row[x*3+2], A: 0xff})
+		}
+	}
+	return rgba, nil
+}
+
+// decodeJPEG decodes the JPEG of the image. CMYK is converted to RGB.
+//
+// Adobe APP14 marks CMYK which is stored inverted where 255 is no ink and image/jpeg undoes it.
+// image/jpeg refuses CMYK without APP14 so that the marker is added and the inversion is undone here.
+// /Decode [1 0 1 0 1 0 1 0] inverts CMYK again.
+func (img Image) decodeJPEG() (image.Image, error) {
+	b, err := img.JPEG()
+	if err != nil {
+		return nil, err
+	}
+
+	adobe, components := jpegMarkers(b)
+	invert := false
+	if components == 4 && !adobe {
+		b = append([]byte{0xff, 0xd8, 0xff, 0xee, 0, 14, 'A', 'd', 'o', 'b', 'e', 0, 100, 0, 0, 0, 0, 0}, b[2:]...)
+		invert = true
+	}
+
+	decoded, err := jpeg.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode JPEG: %w", err)
+	}
+	cmyk, ok := decoded.(*image.CMYK)
+	if !ok {
+		return decoded, nil
+	}
+
+	if decode, ok := img.Dict["Decode"].(PDFArray); ok && len(decode) == 8 {
+		if lo, _ := toFloat(decode[0]); lo == 1 {
+			invert = !invert
+		}
+	}
+
+	rgba := image.NewNRGBA(cmyk.Rect)
+	for y := cmyk.Rect.Min.Y; y < cmyk.Rect.Max.Y; y++ {
+		for x := cmyk.Rect.Min.X; x < cmyk.Rect.Max.X; x++ {
+			c := cmyk.CMYKAt(x, y)
+			if invert {
+				c = color.CMYK{C: 255 - c.C, M: 255 - c.M, Y: 255 - c.Y, K: 255 - c.K}
+			}
+			r, g, b := color.CMYKToRGB(c.C, c.M, c.Y, c.K)
+			rgba.SetNRGBA(x, y, color.NRGBA{R: r, G: g, B: b, A: 0xff})
+		}
+	}
+	return rgba, nil
+}
+
+// jpegMarkers returns whether the JPEG has Adobe APP14 and the number of components in the frame header.
+// ITU-T T.81 B.2 the markers before the scan are followed by their length.
+func jpegMarkers(b []byte) (bool, int) {
+	var adobe bool
+	if len(b) < 2 || b[0] != 0xff || b[1] != 0xd8 {
+		return false, 0
+	}
+	for i := 2; i+4 <= len(b); {
+		if b[i] != 0xff {
+			return adobe, 0
+		}
+		marker := b[i+1]
+		if marker == 0xff {
+			// fill bytes
+			i++
+			continue
+		}
+		length := int(b[i+2])<<8 | int(b[i+3])
+		segment := b[i+4:]
+		if length < 2 || len(segment) < length-2 {
+			return adobe, 0
+		}
+		segment = segment[:length-2]
+
+		switch {
+		case marker == 0xee && bytes.HasPrefix(segment, []byte("Adobe")):
+			adobe = true
+		case marker >= 0xc0 && marker <= 0xcf && marker != 0xc4 && marker != 0xc8 && marker != 0xcc:
+			// SOFn: precision, height, width and the number of components
+			if len(segment) < 6 {
+				return adobe, 0
+			}
+			return adobe, int(segment[5])
+		case marker == 0xda:
+			return adobe, 0
+		}
+		i += 2 + length
+	}
+	return adobe, 0
+}
+
+// decodeMonochrome decodes 1 bit per pixel whose rows start at byte boundaries.
+// 0 is black unless /Decode is [1 0]. For an image mask, 0 is painted.
+func (img Image) decodeMonochrome() (image.Image, error) {
+	_, b, err := img.d.readStream(img.Ref)
+	if err != nil {
+		return nil, err
+	}
+	stride := (img.Width + 7) / 8
+	if len(b)/stride < img.Height {
+		return nil, fmt.Errorf("image data is too short: %d bytes for %dx%d", len(b), img.Width, img.Height)
+	}
+
+	var invert bool
+	if decode, ok := img.Dict["Decode"].(PDFArray); ok && len(decode) == 2 {
+		lo, _ := toFloat(decode[0])
+		invert = lo == 1
+	}
+
+	gray := image.NewGray(image.Rect(0, 0, img.Width, img.Height))
+	for y := 0; y < img.Height; y++ {
+		row := b[y*stride:]
+		for x := 0; x < img.Width; x++ {
+			if (row[x/8]&(0x80>>uint(x%8)) != 0) != invert {
+				gray.Pix[y*gray.Stride+x] = 0xff
+			}
+		}
+	}
+	return gray, nil
+}
@@ -0,0 +1,128 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// 14.3.3 Document Information Dictionary
+type DocInfo struct {
+	Title    string
+	Author   string
+	Subject  string
+	Keywords string
+	Creator  string
+	Producer string
+
+	// dates are zero when missing or malformed
+	CreationDate time.Time
+	ModDate      time.Time
+}
+
+// Info returns the document information dictionary referenced by the trailer.
+// An empty DocInfo is returned if the trailer has no /Info.
+func (d *Document) Info() (DocInfo, error) {
+	var info DocInfo
+
+	obj, ok := d.trailerDict["Info"]
+	if !ok {
+		return info, nil
+	}
+	dict, err := d.resolveDict(obj)
+	if err != nil {
+		return info, fmt.Errorf("unable to resolve /Info: %w", err)
+	}
+
+	text := func(key string) string {
+		obj, err := d.Resolve(dict[key])
+		if err != nil {
+			return ""
+		}
+		s, _ := obj.(PDFString)
+		return DecodeTextString(s)
+	}
+	date := func(key string) time.Time {
+		t, _ := ParseDate(text(key))
+		return t
+	}
+
+	info.Title = text("Title")
+	info.Author = text("Author")
+	info.Subject = text("Subject")
+	info.Keywords = text("Keywords")
+	info.Creator = text("Creator")
+	info.Producer = text("Producer")
+	info.CreationDate = date("CreationDate")
+	info.ModDate = date("ModDate")
+
+	return info, nil
+}
+
+// ParseDate parses a date string in the form of D:YYYYMMDDHHmmSSOHH'mm'.
+// All fields after the year are optional. The time is in UTC when the offset is missing.
+// 7.9.4 Dates
+func ParseDate(s string) (time.Time, error) {
+	if len(s) >= 2 && s[:2] == "D:" {
+		s = s[2:]
+	}
+	if len(s) < 4 {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+
+	// year, month, day, hour, minute and second with defaults
+	fields := []int{0, 1, 1, 0, 0, 0}
+	widths := []int{4, 2, 2, 2, 2, 2}
+	pos := 0
+	for i, w := range widths {
+		if pos+w > len(s) || !isDigits(s[pos:pos+w]) {
+			break
+		}
+		fields[i], _ = strconv.Atoi(s[pos : pos+w])
+		pos += w
+	}
+
+	loc := time.UTC
+	if pos < len(s) {
+		switch sign := s[pos]; sign {
+		case 'Z':
+		case '+', '-':
+			var hh, mm int
+			rest := s[pos+1:]
+			if len(rest) < 2 || !isDigits(rest[:2]) {
+				return time.Time{}, fmt.Errorf("invalid offset in date %q", s)
+			}
+			hh, _ = strconv.Atoi(rest[:2])
+			rest = rest[2:]
+			if len(rest) > 0 && rest[0] == '\'' {
+				rest = rest[1:]
+			}
+			if len(rest) >= 2 && isDigits(rest[:2]) {
+				mm, _ = strconv.Atoi(rest[:2])
+			}
+			offset := hh*3600 + mm*60
+			if sign == '-' {
+				offset = -offset
+			}
+			loc = time.FixedZone("", offset)
+		default:
+			return time.Time{}, fmt.Errorf("invalid date %q", s)
+		}
+	}
+
+	t := time.Date(fields[0], time.Month(fields[1]), fields[2], fields[3], fields[4], fields[5], 0, loc)
+	if t.Month() != time.Month(fields[1]) || t.Day() != fields[2] || fields[3] > 23 || fields[4] > 59 || fields[5] > 59 {
+		return time.Time{}, errors.New("date is out of range")
+	}
+	return t, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
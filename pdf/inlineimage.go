@@ -0,0 +1,211 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+)
+
+// InlineImage is an image embedded in a content stream.
+// 8.9.7 Inline Images
+type InlineImage struct {
+	// Dict is the image dictionary with the abbreviations expanded
+	Dict PDFDict
+	Data []byte
+}
+
+// InlineImage returns the inline image of a BI operator.
+func (op ContentOp) InlineImage() (InlineImage, bool) {
+	if op.Operator != "BI" || len(op.Operands) != 1 {
+		return InlineImage{}, false
+	}
+	dict, ok := op.Operands[0].(PDFDict)
+	if !ok {
+		return InlineImage{}, false
+	}
+	return InlineImage{Dict: ExpandInlineImageDict(dict), Data: op.Data}, true
+}
+
+// Table 92 Additional abbreviations in an inline image object
+var inlineImageKeys = map[string]string{
+	"BPC": "BitsPerComponent",
+	"CS":  "ColorSpace",
+	"D":   "Decode",
+	"DP":  "DecodeParms",
+	"F":   "Filter",
+	"H":   "Height",
+	"IM":  "ImageMask",
+	"I":   "Interpolate",
+	"L":   "Length",
+	"W":   "Width",
+}
+
+// Table 93 Additional abbreviations in an inline image object
+// They are accepted in streams too by canonicalColorSpace and canonicalFilter.
+var colorSpaceAbbreviations = map[PDFName]PDFName{
+	"G":    "DeviceGray",
+	"RGB":  "DeviceRGB",
+	"CMYK": "DeviceCMYK",
+	"I":    "Indexed",
+}
+
+var filterAbbreviations = map[PDFName]PDFName{
+	"AHx": "ASCIIHexDecode",
+	"A85": "ASCII85Decode",
+	"LZW": "LZWDecode",
+	"Fl":  "FlateDecode",
+	"RL":  "RunLengthDecode",
+	"CCF": "CCITTFaxDecode",
+	"DCT": "DCTDecode",
+}
+
+// ExpandInlineImageDict returns a copy of the inline image dictionary whose abbreviated keys,
+// color space names and filter names are replaced with the full ones used by image XObjects.
+// A color space which is not abbreviated is a name in /ColorSpace of the resources and is kept as-is.
+func ExpandInlineImageDict(dict PDFDict) PDFDict {
+	expanded := make(PDFDict, len(dict))
+	for k, v := range dict {
+		if full, ok := inlineImageKeys[k]; ok {
+			k = full
+		}
+		expanded[k] = v
+	}
+
+	if cs, ok := expanded["ColorSpace"]; ok {
+		expanded["ColorSpace"] = expandNames(cs, colorSpaceAbbreviations)
+	}
+	if f, ok := expanded["Filter"]; ok {
+		expanded["Filter"] = expandNames(f, filterAbbreviations)
+	}
+	return expanded
+}
+
+// canonicalFilter returns the full name of a filter which may be abbreviated such as FlateDecode for Fl.
+func canonicalFilter(name PDFName) PDFName {
+	if full, ok := filterAbbreviations[name]; ok {
+		return full
+	}
+	return name
+}
+
+// canonicalColorSpace returns the full name of a color space family which may be abbreviated such as DeviceRGB for RGB.
+func canonicalColorSpace(name PDFName) PDFName {
+	if full, ok := colorSpaceAbbreviations[name]; ok {
+		return full
+	}
+	return name
+}
+
+// expandNames replaces a name or names in an array with the ones in names.
+func expandNames(obj PDFObject, names map[PDFName]PDFName) PDFObject {
+	switch obj := obj.(type) {
+	case PDFName:
+		if full, ok := names[obj]; ok {
+			return full
+		}
+	case PDFArray:
+		arr := make(PDFArray, len(obj))
+		for i, elem := range obj {
+			arr[i] = expandNames(elem, names)
+		}
+		return arr
+	}
+	return obj
+}
+
+// inlineImageDataEnd returns where the image data starting at b[0] ends when it can be told from the dictionary.
+// It is the explicit /L, the size of an unfiltered image or the end of data of the first filter.
+// The image data can contain EI so it is more reliable than looking for EI.
+func inlineImageDataEnd(b []byte, dict PDFDict) (int64, bool) {
+	if l, ok := dict["Length"].(PDFInt); ok && l >= 0 && int64(l) <= int64(len(b)) {
+		return int64(l), true
+	}
+
+	var filter PDFName
+	switch f := dict["Filter"].(type) {
+	case nil:
+		return unfilteredImageSize(dict, int64(len(b)))
+	case PDFName:
+		filter = f
+	case PDFArray:
+		if len(f) == 0 {
+			return unfilteredImageSize(dict, int64(len(b)))
+		}
+		filter, _ = f[0].(PDFName)
+	}
+
+	switch filter {
+	case "ASCIIHexDecode":
+		if n := bytes.IndexByte(b, '>'); n >= 0 {
+			return int64(n) + 1, true
+		}
+	case "ASCII85Decode":
+		if n := bytes.Index(b, []byte("~>")); n >= 0 {
+			return int64(n) + 2, true
+		}
+	case "FlateDecode":
+		// the decompressor reads no more than the compressed data from an io.ByteReader
+		r := bytes.NewReader(b)
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			return 0, false
+		}
+		if _, err := io.CopyN(io.Discard, zr, DefaultMaxDecodedSize); err != io.EOF {
+			return 0, false
+		}
+		return int64(len(b) - r.Len()), true
+	case "RunLengthDecode":
+		// 7.4.5 a length byte of 128 is EOD
+		for i := 0; i < len(b); {
+			switch n := int(b[i]); {
+			case n == 128:
+				return int64(i) + 1, true
+			case n < 128:
+				i += n + 2
+			default:
+				i += 2
+			}
+		}
+	}
+	return 0, false
+}
+
+// unfilteredImageSize returns the size of the samples of an image without a filter.
+// 8.9.3 Sample Representation: each row is padded to a byte boundary.
+func unfilteredImageSize(dict PDFDict, max int64) (int64, bool) {
+	w, ok1 := dict["Width"].(PDFInt)
+	h, ok2 := dict["Height"].(PDFInt)
+	if !ok1 || !ok2 || w <= 0 || h <= 0 {
+		return 0, false
+	}
+
+	bpc, components := int64(8), int64(0)
+	if mask, _ := dict["ImageMask"].(PDFBool); mask {
+		bpc, components = 1, 1
+	} else {
+		if v, ok := dict["BitsPerComponent"].(PDFInt); ok {
+			bpc = int64(v)
+		}
+		cs := dict["ColorSpace"]
+		if arr, ok := cs.(PDFArray); ok && len(arr) > 0 {
+			cs = arr[0]
+		}
+		switch cs {
+		case PDFName("DeviceGray"), PDFName("CalGray"), PDFName("Indexed"):
+			components = 1
+		case PDFName("DeviceRGB"), PDFName("CalRGB"), PDFName("Lab"):
+			components = 3
+		case PDFName("DeviceCMYK"):
+			components = 4
+		}
+	}
+	if components == 0 || bpc <= 0 || bpc > 16 || int64(w) > max || int64(h) > max {
+		return 0, false
+	}
+
+	size := (int64(w)*components*bpc + 7) / 8 * int64(h)
+	if size > max {
+		return 0, false
+	}
+	return size, true
+}
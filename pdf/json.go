@@ -0,0 +1,155 @@
+package pdf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSON representation of the object model:
+// dictionaries are objects, arrays are arrays, names are {"name": "Foo"},
+// references are {"ref": [12, 0]}, strings are base64 since they can be binary
+// and streams are {"stream": {"dict": {...}, "length": 123}}.
+// encoding/json sorts the keys of dictionaries so the output is stable.
+
+func (d PDFDict) MarshalJSON() ([]byte, error) {
+	if d == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]PDFObject(d))
+}
+
+func (a PDFArray) MarshalJSON() ([]byte, error) {
+	if a == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]PDFObject(a))
+}
+
+func (n PDFName) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name string `json:"name"`
+	}{string(n)})
+}
+
+func (s PDFString) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]byte(s))
+}
+
+func (i PDFInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(i))
+}
+
+func (r PDFReal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(r))
+}
+
+func (b PDFBool) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bool(b))
+}
+
+func (PDFNull) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+func (r PDFRef) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Ref [2]int64 `json:"ref"`
+	}{[2]int64{r.Number, int64(r.Generation)}})
+}
+
+// MarshalJSON uses direct /Length as the length since the body is not a part of PDFStream.
+func (s PDFStream) MarshalJSON() ([]byte, error) {
+	js := jsonStream{Dict: s.Dict}
+	if n, ok := s.Dict["Length"].(PDFInt); ok {
+		js.Length = int64(n)
+	}
+	return json.Marshal(struct {
+		Stream jsonStream `json:"stream"`
+	}{js})
+}
+
+type jsonStream struct {
+	Dict   PDFDict `json:"dict"`
+	Length int64   `json:"length"`
+	// Data is the decoded body (base64)
+	Data []byte `json:"data,omitempty"`
+}
+
+type jsonObject struct {
+	Number     int64 `json:"number"`
+	Generation int   `json:"generation"`
+	// Object is PDFObject or jsonStreamObject
+	Object interface{} `json:"object,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// DumpJSON writes the trailer and all objects in use as JSON to w.
+// The length of a stream is the length of its raw body and
+// the decoded body is included when decoded is true.
+// An object that cannot be read is dumped with its error instead of failing the whole dump.
+func (d *Document) DumpJSON(w io.Writer, decoded bool) error {
+	objects := []jsonObject{}
+	for _, ent := range d.entries {
+		if !ent.InUse {
+			continue
+		}
+
+		ref := PDFRef{Number: ent.Number, Generation: ent.Generation}
+		jo := jsonObject{Number: ent.Number, Generation: ent.Generation}
+
+		obj, err := d.Resolve(ref)
+		if err != nil {
+			jo.Error = err.Error()
+			objects = append(objects, jo)
+			continue
+		}
+		jo.Object = obj
+
+		if stream, ok := obj.(PDFStream); ok {
+			js, err := d.jsonStream(ent, stream, decoded)
+			if err != nil {
+				jo.Error = err.Error()
+			} else {
+				jo.Object = jsonStreamObject{js}
+			}
+		}
+
+		objects = append(objects, jo)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(struct {
+		Trailer PDFDict      `json:"trailer"`
+		Objects []jsonObject `json:"objects"`
+	}{d.trailerDict, objects}); err != nil {
+		return fmt.Errorf("unable to encode JSON: %w", err)
+	}
+	return nil
+}
+
+func (d *Document) jsonStream(ent XrefEntry, stream PDFStream, decoded bool) (jsonStream, error) {
+	js := jsonStream{Dict: stream.Dict}
+
+	// compressed objects are never streams
+	raw, err := d.ReadStreamBody(ent, stream.Dict)
+	if err != nil {
+		return js, err
+	}
+	js.Length = int64(len(raw))
+
+	if decoded {
+		b, err := DecodeStreamWithLimit(stream.Dict, raw, d.decodedSizeLimit())
+		if err != nil {
+			return js, err
+		}
+		js.Data = b
+	}
+	return js, nil
+}
+
+// jsonStreamObject is a stream whose length is taken from its body.
+type jsonStreamObject struct {
+	Stream jsonStream `json:"stream"`
+}
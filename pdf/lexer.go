@@ -0,0 +1,450 @@
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 7.2 Lexical Conventions
+type TokenKind int
+
+const (
+	TokenEOF TokenKind = iota
+	TokenDictBegin
+	TokenDictEnd
+	TokenArrayBegin
+	TokenArrayEnd
+	TokenName
+	TokenString
+	TokenHexString
+	TokenInteger
+	TokenReal
+	TokenKeyword
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case TokenEOF:
+		return "EOF"
+	case TokenDictBegin:
+		return "<<"
+	case TokenDictEnd:
+		return ">>"
+	case TokenArrayBegin:
+		return "["
+	case TokenArrayEnd:
+		return "]"
+	case TokenName:
+		return "name"
+	case TokenString:
+		return "string"
+	case TokenHexString:
+		return "hex string"
+	case TokenInteger:
+		return "integer"
+	case TokenReal:
+		return "real"
+	case TokenKeyword:
+		return "keyword"
+	}
+	return "unknown"
+}
+
+// Token is a lexical token.
+// Value holds the decoded name without the solidus, the decoded bytes of strings
+// and the raw bytes otherwise.
+type Token struct {
+	Kind  TokenKind
+	Value string
+}
+
+func (t Token) Is(kind TokenKind, value string) bool {
+	return t.Kind == kind && t.Value == value
+}
+
+// 7.2.2 Character Set
+func isWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func isRegular(c byte) bool {
+	return !isWhitespace(c) && !isDelimiter(c)
+}
+
+type Lexer struct {
+	r      *bufio.Reader
+	offset int64
+}
+
+func NewLexer(r io.Reader) *Lexer {
+	// a small object in memory such as one in an object stream does not need the default buffer
+	size := 4096
+	if br, ok := r.(*bytes.Reader); ok && br.Len() < size {
+		size = br.Len()
+	}
+	return &Lexer{r: bufio.NewReaderSize(r, size)}
+}
+
+// Offset returns the number of bytes consumed so far.
+func (l *Lexer) Offset() int64 {
+	return l.offset
+}
+
+func (l *Lexer) readByte() (byte, error) {
+	c, err := l.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	l.offset++
+	return c, nil
+}
+
+func (l *Lexer) unreadByte() {
+	if err := l.r.UnreadByte(); err == nil {
+		l.offset--
+	}
+}
+
+func (l *Lexer) peekByte() (byte, error) {
+	b, err := l.r.Peek(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// skipSpace skips whitespace and comments.
+func (l *Lexer) skipSpace() error {
+	for {
+		c, err := l.readByte()
+		if err != nil {
+			return err
+		}
+
+		if isWhitespace(c) {
+			continue
+		}
+
+		if c == '%' {
+			// 7.2.3 Comments
+			for {
+				c, err := l.readByte()
+				if err != nil {
+					return err
+				}
+				if c == '\r' || c == '\n' {
+					break
+				}
+			}
+			continue
+		}
+
+		l.unreadByte()
+		return nil
+	}
+}
+
+func (l *Lexer) readRegular() (string, error) {
+	var b []byte
+	for {
+		c, err := l.readByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if !isRegular(c) {
+			l.unreadByte()
+			break
+		}
+		b = append(b, c)
+	}
+	return string(b), nil
+}
+
+// Next returns the next token. It returns a token with TokenEOF at the end of input.
+func (l *Lexer) Next() (Token, error) {
+	if err := l.skipSpace(); err != nil {
+		if err == io.EOF {
+			return Token{Kind: TokenEOF}, nil
+		}
+		return Token{}, err
+	}
+
+	c, err := l.readByte()
+	if err != nil {
+		return Token{}, err
+	}
+
+	switch c {
+	case '[':
+		return Token{Kind: TokenArrayBegin, Value: "["}, nil
+	case ']':
+		return Token{Kind: TokenArrayEnd, Value: "]"}, nil
+	case '/':
+		name, err := l.readRegular()
+		if err != nil {
+			return Token{}, err
+		}
+		return Token{Kind: TokenName, Value: decodeName(name)}, nil
+	case '(':
+		return l.readLiteralString()
+	case '<':
+		if next, err := l.peekByte(); err == nil && next == '<' {
+			l.readByte()
+			return Token{Kind: TokenDictBegin, Value: "<<"}, nil
+		}
+		return l.readHexString()
+	case '>':
+		if next, err := l.peekByte(); err == nil && next == '>' {
+			l.readByte()
+			return Token{Kind: TokenDictEnd, Value: ">>"}, nil
+		}
+		return Token{}, errors.New("unexpected '>'")
+	case ')', '{', '}':
+		return Token{}, fmt.Errorf("unexpected %q", c)
+	}
+
+	l.unreadByte()
+	tok, err := l.readRegular()
+	if err != nil {
+		return Token{}, err
+	}
+
+	if c := tok[0]; c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9') {
+		kind, err := numberKind(tok)
+		if err != nil {
+			return Token{}, err
+		}
+		return Token{Kind: kind, Value: tok}, nil
+	}
+	return Token{Kind: TokenKeyword, Value: tok}, nil
+}
+
+// numberKind returns whether tok is an integer or a real.
+// 7.3.3 Numeric Objects: a real has a leading or trailing period such as .5 and 4. but no exponent.
+// An exponent such as 1e-05 is accepted as written by some producers and a dangling e as in 34.5e is ignored.
+// An integer out of the range of int64 is a real.
+func numberKind(tok string) (TokenKind, error) {
+	s := tok
+	if s[0] == '+' || s[0] == '-' {
+		s = s[1:]
+	}
+	intPart := countDigits(s)
+	s = s[intPart:]
+
+	kind := TokenInteger
+	if len(s) > 0 && s[0] == '.' {
+		kind = TokenReal
+		s = s[1:]
+		if countDigits(s) == 0 && intPart == 0 {
+			return 0, fmt.Errorf("malformed number %q", tok)
+		}
+		s = s[countDigits(s):]
+	}
+	if intPart == 0 && kind == TokenInteger {
+		return 0, fmt.Errorf("malformed number %q", tok)
+	}
+
+	if len(s) > 0 && (s[0] == 'e' || s[0] == 'E') {
+		kind = TokenReal
+		s = s[1:]
+		if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+			s = s[1:]
+		}
+		s = s[countDigits(s):]
+	}
+	if len(s) > 0 {
+		return 0, fmt.Errorf("malformed number %q", tok)
+	}
+
+	if kind == TokenInteger {
+		if _, err := strconv.ParseInt(tok, 10, 64); err != nil {
+			kind = TokenReal
+		}
+	}
+	return kind, nil
+}
+
+// parseReal parses a real accepted by numberKind.
+func parseReal(s string) (float64, error) {
+	if n := len(s); n > 0 && (s[n-1] == 'e' || s[n-1] == 'E') {
+		s = s[:n-1]
+	} else if n > 1 && (s[n-1] == '+' || s[n-1] == '-') && (s[n-2] == 'e' || s[n-2] == 'E') {
+		s = s[:n-2]
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func countDigits(s string) int {
+	n := 0
+	for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+	return n
+}
+
+// 7.3.4.2 Literal Strings
+func (l *Lexer) readLiteralString() (Token, error) {
+	var s []byte
+	depth := 1
+	for {
+		c, err := l.readByte()
+		if err == io.EOF {
+			return Token{}, errors.New("unterminated literal string")
+		}
+		if err != nil {
+			return Token{}, err
+		}
+
+		switch c {
+		case '\\':
+			b, ok, err := l.readEscape()
+			if err != nil {
+				return Token{}, err
+			}
+			if ok {
+				s = append(s, b)
+			}
+			continue
+		case '\r':
+			// an end-of-line marker is read as \n
+			if next, err := l.peekByte(); err == nil && next == '\n' {
+				l.readByte()
+			}
+			c = '\n'
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return Token{Kind: TokenString, Value: string(s)}, nil
+			}
+		}
+		s = append(s, c)
+	}
+}
+
+// readEscape reads an escape sequence after a backslash.
+// It returns false for a line continuation which produces no byte.
+// Table 3 Escape sequences in literal strings
+func (l *Lexer) readEscape() (byte, bool, error) {
+	c, err := l.readByte()
+	if err == io.EOF {
+		return 0, false, errors.New("unterminated literal string")
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch c {
+	case 'n':
+		return '\n', true, nil
+	case 'r':
+		return '\r', true, nil
+	case 't':
+		return '\t', true, nil
+	case 'b':
+		return '\b', true, nil
+	case 'f':
+		return '\f', true, nil
+	case '\r':
+		if next, err := l.peekByte(); err == nil && next == '\n' {
+			l.readByte()
+		}
+		return 0, false, nil
+	case '\n':
+		return 0, false, nil
+	}
+
+	if c < '0' || c > '7' {
+		// the backslash is ignored for any other character including \\, \( and \)
+		return c, true, nil
+	}
+
+	// up to three octal digits. High-order overflow is ignored.
+	n := int(c - '0')
+	for i := 0; i < 2; i++ {
+		next, err := l.peekByte()
+		if err != nil || next < '0' || next > '7' {
+			break
+		}
+		l.readByte()
+		n = n*8 + int(next-'0')
+	}
+	return byte(n), true, nil
+}
+
+// decodeName decodes #xx in a name into the byte.
+// A # not followed by two hexadecimal digits is kept as-is as in PDF 1.1 and earlier.
+// 7.3.5 Name Objects
+func decodeName(name string) string {
+	if !strings.Contains(name, "#") {
+		return name
+	}
+
+	b := make([]byte, 0, len(name))
+	for i := 0; i < len(name); i++ {
+		if name[i] == '#' && i+2 < len(name) {
+			hi, ok1 := hexValue(name[i+1])
+			lo, ok2 := hexValue(name[i+2])
+			if ok1 && ok2 {
+				b = append(b, hi<<4|lo)
+				i += 2
+				continue
+			}
+		}
+		b = append(b, name[i])
+	}
+	return string(b)
+}
+
+// 7.3.4.3 Hexadecimal Strings
+func (l *Lexer) readHexString() (Token, error) {
+	var digits []byte
+	for {
+		c, err := l.readByte()
+		if err == io.EOF {
+			return Token{}, errors.New("unterminated hex string")
+		}
+		if err != nil {
+			return Token{}, err
+		}
+
+		if c == '>' {
+			break
+		}
+		if isWhitespace(c) {
+			continue
+		}
+		digits = append(digits, c)
+	}
+
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	s, err := hex.DecodeString(string(digits))
+	if err != nil {
+		return Token{}, fmt.Errorf("unable to decode hex string: %w", err)
+	}
+	return Token{Kind: TokenHexString, Value: string(s)}, nil
+}
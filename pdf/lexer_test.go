@@ -0,0 +1,173 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// lexAll returns the tokens of s before EOF.
+func lexAll(s string) ([]Token, error) {
+	lex := NewLexer(strings.NewReader(s))
+	var toks []Token
+	for {
+		tok, err := lex.Next()
+		if err != nil {
+			return toks, err
+		}
+		if tok.Kind == TokenEOF {
+			return toks, nil
+		}
+		toks = append(toks, tok)
+	}
+}
+
+func TestLexNumber(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		kind TokenKind
+		want PDFObject
+	}{
+		{in: "123", kind: TokenInteger, want: PDFInt(123)},
+		{in: "+10", kind: TokenInteger, want: PDFInt(10)},
+		{in: "-98", kind: TokenInteger, want: PDFInt(-98)},
+		{in: "0000123", kind: TokenInteger, want: PDFInt(123)},
+		{in: "34.5", kind: TokenReal, want: PDFReal(34.5)},
+		{in: ".5", kind: TokenReal, want: PDFReal(0.5)},
+		{in: "4.", kind: TokenReal, want: PDFReal(4)},
+		{in: "-.25", kind: TokenReal, want: PDFReal(-0.25)},
+		{in: "-.002", kind: TokenReal, want: PDFReal(-0.002)},
+		{in: "+.5", kind: TokenReal, want: PDFReal(0.5)},
+		// an exponent is not in the grammar but is written by some producers
+		{in: "1e-05", kind: TokenReal, want: PDFReal(0.00001)},
+		{in: "2.5E3", kind: TokenReal, want: PDFReal(2500)},
+		// a dangling exponent marker is ignored
+		{in: "34.5e", kind: TokenReal, want: PDFReal(34.5)},
+		{in: "1e-", kind: TokenReal, want: PDFReal(1)},
+		// an integer too large for int64 is a real
+		{in: "99999999999999999999", kind: TokenReal, want: PDFReal(1e20)},
+	} {
+		toks, err := lexAll(tc.in)
+		if err != nil {
+			t.Errorf("%q: %v", tc.in, err)
+			continue
+		}
+		if len(toks) != 1 || toks[0].Kind != tc.kind {
+			t.Errorf("%q: got %v, want a token of %s", tc.in, toks, tc.kind)
+			continue
+		}
+
+		obj, err := ParseObject([]byte(tc.in))
+		if err != nil {
+			t.Errorf("%q: %v", tc.in, err)
+			continue
+		}
+		if obj != tc.want {
+			t.Errorf("%q: got %#v, want %#v", tc.in, obj, tc.want)
+		}
+	}
+}
+
+func TestLexMalformedNumber(t *testing.T) {
+	for _, in := range []string{"-", "+", ".", "+.", "-.", "1.2.3", "--5", "+-5", "5-", "12abc", "-e5", ".e5", "1e5x"} {
+		_, err := lexAll(in)
+		if err == nil || !strings.Contains(err.Error(), "malformed number") {
+			t.Errorf("%q: expected a malformed number but got %v", in, err)
+		}
+		// the error is not hidden by backtracking in the parser
+		if _, err := ParseObject([]byte(in)); err == nil || !strings.Contains(err.Error(), "malformed number") {
+			t.Errorf("ParseObject(%q): expected a malformed number but got %v", in, err)
+		}
+	}
+
+	// a keyword is not a number
+	toks, err := lexAll("R obj e5 true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tok := range toks {
+		if tok.Kind != TokenKeyword {
+			t.Errorf("%q: got %s, want a keyword", tok.Value, tok.Kind)
+		}
+	}
+}
+
+func TestLexLiteralString(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{in: `(These \(are\) balanced)`, want: "These (are) balanced"},
+		// 7.3.4.2 balanced parentheses need no escape
+		{in: `(Strings may contain balanced parentheses ( ) and special characters (*!&}^% and so on).)`,
+			want: "Strings may contain balanced parentheses ( ) and special characters (*!&}^% and so on)."},
+		{in: `()`, want: ""},
+		{in: `(\n\r\t\b\f\(\)\\)`, want: "\n\r\t\b\f()\\"},
+		// octal escapes of one to three digits
+		{in: `(\245\53\0)`, want: "\xa5+\x00"},
+		{in: `(\0053)`, want: "\x053"},
+		{in: `(\7)`, want: "\x07"},
+		// high-order overflow is ignored
+		{in: `(\777)`, want: "\xff"},
+		// a backslash at the end of a line continues the string
+		{in: "(These \\\ntwo strings \\\r\nare the same.)", want: "These two strings are the same."},
+		// an end-of-line marker in the string is read as \n
+		{in: "(a\r\nb\rc\nd)", want: "a\nb\nc\nd"},
+		// a backslash before another character is ignored
+		{in: `(\q)`, want: "q"},
+	} {
+		toks, err := lexAll(tc.in)
+		if err != nil {
+			t.Errorf("%q: %v", tc.in, err)
+			continue
+		}
+		if len(toks) != 1 || toks[0].Kind != TokenString || toks[0].Value != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.in, toks, tc.want)
+		}
+	}
+
+	for _, in := range []string{`(unterminated`, `(unbalanced ( )`, `(escape \`} {
+		if _, err := lexAll(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestLexName(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{in: "/Name1", want: "Name1"},
+		{in: "/A;Name_With-Various***Characters?", want: "A;Name_With-Various***Characters?"},
+		{in: "/A#20B", want: "A B"},
+		{in: "/Adobe#3a", want: "Adobe:"},
+		{in: "/paired#28#29parentheses", want: "paired()parentheses"},
+		{in: "/The_Key_of_F#23_Minor", want: "The_Key_of_F#_Minor"},
+		// a # not followed by two hexadecimal digits is kept as in PDF 1.1
+		{in: "/lone#", want: "lone#"},
+		{in: "/lone#4", want: "lone#4"},
+		{in: "/not#zzhex", want: "not#zzhex"},
+		{in: "/", want: ""},
+	} {
+		toks, err := lexAll(tc.in)
+		if err != nil {
+			t.Errorf("%q: %v", tc.in, err)
+			continue
+		}
+		if len(toks) != 1 || toks[0].Kind != TokenName || toks[0].Value != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.in, toks, tc.want)
+			continue
+		}
+
+		// the renderer escapes the name so that it is read back as the same name
+		rendered := PDFName(tc.want).String()
+		toks, err = lexAll(rendered)
+		if err != nil || len(toks) != 1 || toks[0].Value != tc.want {
+			t.Errorf("%q: %q is read back as %q, %v", tc.in, rendered, toks, err)
+		}
+	}
+
+	for name, want := range map[PDFName]string{"A B": "/A#20B", "Adobe:": "/Adobe:", "lone#": "/lone#23", "a/b(c)": "/a#2Fb#28c#29"} {
+		if got := name.String(); got != want {
+			t.Errorf("%q: got %s, want %s", string(name), got, want)
+		}
+	}
+}
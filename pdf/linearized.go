@@ -0,0 +1,47 @@
+package pdf
+
+import (
+	"fmt"
+)
+
+// IsLinearized reports whether the file is linearized and returns the linearization parameter dictionary.
+// Annex F Linearized PDF
+// The dictionary is the first object in the file. A dictionary whose /L does not match the file size
+// means the file was updated after it was linearized and it is not reported as linearized.
+func (d *Document) IsLinearized() (bool, PDFDict, error) {
+	base, _, err := readHeader(d.ra)
+	if err != nil {
+		return false, nil, err
+	}
+
+	// the first object follows the header and the comment of binary characters
+	head := make([]byte, 1024)
+	n, _ := d.ra.ReadAt(head, base)
+	loc := objectHeaderPattern.FindIndex(head[:n])
+	if loc == nil {
+		return false, nil, nil
+	}
+
+	b, err := readEntry(XrefEntry{ByteOffset: base + int64(loc[0])}, d.ra)
+	if err != nil {
+		return false, nil, fmt.Errorf("unable to read the first object: %w", err)
+	}
+	obj, err := ParseObject(b)
+	if err != nil {
+		return false, nil, fmt.Errorf("unable to parse the first object: %w", err)
+	}
+
+	dict, ok := obj.(PDFDict)
+	if !ok {
+		return false, nil, nil
+	}
+	if _, ok := dict["Linearized"]; !ok {
+		return false, nil, nil
+	}
+
+	// Table F.1 /L is the length of the entire file in bytes
+	if l, ok := dict["L"].(PDFInt); !ok || int64(l) != d.trailer.size-base {
+		return false, dict, nil
+	}
+	return true, dict, nil
+}
@@ -0,0 +1,58 @@
+package pdf
+
+import "math"
+
+// Matrix is a transformation matrix [a b c d e f] which stands for
+//
+//	a b 0
+//	c d 0
+//	e f 1
+//
+// 8.3.4 Transformation Matrices
+type Matrix [6]float64
+
+// IdentityMatrix maps each point to itself.
+var IdentityMatrix = Matrix{1, 0, 0, 1, 0, 0}
+
+// Multiply returns m × n, the transformation of m followed by n.
+func (m Matrix) Multiply(n Matrix) Matrix {
+	return Matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// Transform returns the point (x, y) transformed by m.
+func (m Matrix) Transform(x, y float64) (float64, float64) {
+	return x*m[0] + y*m[2] + m[4], x*m[1] + y*m[3] + m[5]
+}
+
+// translateMatrix returns the matrix which moves by (tx, ty).
+func translateMatrix(tx, ty float64) Matrix {
+	return Matrix{1, 0, 0, 1, tx, ty}
+}
+
+// matrixOf returns the matrix of 6 numbers as the operands of cm and Tm.
+func matrixOf(operands []PDFObject) (Matrix, bool) {
+	var m Matrix
+	if len(operands) != 6 {
+		return m, false
+	}
+	for i, obj := range operands {
+		v, ok := toFloat(obj)
+		if !ok {
+			return m, false
+		}
+		m[i] = v
+	}
+	return m, true
+}
+
+// yScale returns the length of the unit vector of the y axis transformed by m.
+func (m Matrix) yScale() float64 {
+	return math.Hypot(m[2], m[3])
+}
@@ -0,0 +1,143 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// WalkNameTree calls fn for each key and value in the name tree in the order of keys.
+// 7.9.6 Name Trees
+func (d *Document) WalkNameTree(root PDFDict, fn func(key string, val PDFObject) error) error {
+	return d.walkTree(root, "Names", map[PDFRef]bool{}, nil, 1, func(key, val PDFObject) error {
+		s, ok := key.(PDFString)
+		if !ok {
+			return fmt.Errorf("name tree key must be a string but got %T", key)
+		}
+		return fn(string(s), val)
+	})
+}
+
+// WalkNumberTree calls fn for each key and value in the number tree in the order of keys.
+// 7.9.7 Number Trees
+func (d *Document) WalkNumberTree(root PDFDict, fn func(key int64, val PDFObject) error) error {
+	return d.walkTree(root, "Nums", map[PDFRef]bool{}, nil, 1, func(key, val PDFObject) error {
+		n, ok := key.(PDFInt)
+		if !ok {
+			return fmt.Errorf("number tree key must be an integer but got %T", key)
+		}
+		return fn(int64(n), val)
+	})
+}
+
+// LookupName returns the value of key in the name tree. It returns nil if key is not found.
+// Subtrees whose /Limits do not cover key are skipped.
+func (d *Document) LookupName(root PDFDict, key string) (PDFObject, error) {
+	inRange := func(limits PDFArray) bool {
+		lo, ok1 := limits[0].(PDFString)
+		hi, ok2 := limits[1].(PDFString)
+		return !ok1 || !ok2 || (bytes.Compare([]byte(key), lo) >= 0 && bytes.Compare([]byte(key), hi) <= 0)
+	}
+	return d.lookupTree(root, "Names", inRange, func(k PDFObject) bool {
+		s, ok := k.(PDFString)
+		return ok && string(s) == key
+	})
+}
+
+// LookupNumber returns the value of key in the number tree. It returns nil if key is not found.
+// Subtrees whose /Limits do not cover key are skipped.
+func (d *Document) LookupNumber(root PDFDict, key int64) (PDFObject, error) {
+	inRange := func(limits PDFArray) bool {
+		lo, ok1 := limits[0].(PDFInt)
+		hi, ok2 := limits[1].(PDFInt)
+		return !ok1 || !ok2 || (key >= int64(lo) && key <= int64(hi))
+	}
+	return d.lookupTree(root, "Nums", inRange, func(k PDFObject) bool {
+		n, ok := k.(PDFInt)
+		return ok && int64(n) == key
+	})
+}
+
+var errFound = errors.New("found")
+
+func (d *Document) lookupTree(root PDFDict, leafKey string, inRange func(limits PDFArray) bool, match func(key PDFObject) bool) (PDFObject, error) {
+	var found PDFObject
+	err := d.walkTree(root, leafKey, map[PDFRef]bool{}, inRange, 1, func(key, val PDFObject) error {
+		if match(key) {
+			found = val
+			return errFound
+		}
+		return nil
+	})
+	if err != nil && err != errFound {
+		return nil, err
+	}
+	return found, nil
+}
+
+// walkTree walks a name tree or a number tree whose leaves have pairs in leafKey.
+// When inRange is not nil, intermediate nodes whose /Limits are out of range are skipped.
+// depth is 1 at the root.
+func (d *Document) walkTree(obj PDFObject, leafKey string, visited map[PDFRef]bool, inRange func(limits PDFArray) bool, depth int, fn func(key, val PDFObject) error) error {
+	if err := d.checkDepth(depth, obj); err != nil {
+		return err
+	}
+	if ref, ok := obj.(PDFRef); ok {
+		if visited[ref] {
+			return fmt.Errorf("cyclic tree at %d %d R", ref.Number, ref.Generation)
+		}
+		visited[ref] = true
+	}
+
+	node, err := d.resolveDict(obj)
+	if err != nil {
+		return fmt.Errorf("unable to resolve a tree node: %w", err)
+	}
+
+	// the root node has no /Limits
+	if inRange != nil {
+		if limits, err := d.Resolve(node["Limits"]); err == nil {
+			if arr, ok := limits.(PDFArray); ok && len(arr) == 2 && !inRange(arr) {
+				return nil
+			}
+		}
+	}
+
+	if kidsObj, ok := node["Kids"]; ok {
+		kids, err := d.Resolve(kidsObj)
+		if err != nil {
+			return fmt.Errorf("unable to resolve /Kids: %w", err)
+		}
+		arr, ok := kids.(PDFArray)
+		if !ok {
+			return fmt.Errorf("/Kids must be an array but got %T", kids)
+		}
+		for _, kid := range arr {
+			if err := d.walkTree(kid, leafKey, visited, inRange, depth+1, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	leafObj, ok := node[leafKey]
+	if !ok {
+		return nil
+	}
+	leaf, err := d.Resolve(leafObj)
+	if err != nil {
+		return fmt.Errorf("unable to resolve /%s: %w", leafKey, err)
+	}
+	arr, ok := leaf.(PDFArray)
+	if !ok {
+		return fmt.Errorf("/%s must be an array but got %T", leafKey, leaf)
+	}
+	if len(arr)%2 != 0 {
+		return fmt.Errorf("/%s must have pairs of a key and a value", leafKey)
+	}
+	for i := 0; i < len(arr); i += 2 {
+		if err := fn(arr[i], arr[i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
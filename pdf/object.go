@@ -0,0 +1,306 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// 7.3 Objects
+type PDFObject interface {
+	isPDFObject()
+}
+
+// 7.3.7 Dictionary Objects
+// Keys are stored without the leading solidus.
+type PDFDict map[string]PDFObject
+
+// 7.3.6 Array Objects
+type PDFArray []PDFObject
+
+// 7.3.5 Name Objects
+// The value is stored without the leading solidus.
+type PDFName string
+
+// 7.3.4 String Objects
+type PDFString []byte
+
+// 7.3.3 Numeric Objects
+type PDFInt int64
+type PDFReal float64
+
+// 7.3.2 Boolean Objects
+type PDFBool bool
+
+// 7.3.9 Null Object
+type PDFNull struct{}
+
+// 7.3.10 Indirect Objects
+type PDFRef struct {
+	Number     int64
+	Generation int
+}
+
+// 7.3.8 Stream Objects
+// Only the stream dictionary is kept. The body must be read separately.
+type PDFStream struct {
+	Dict PDFDict
+}
+
+func (PDFDict) isPDFObject()   {}
+func (PDFArray) isPDFObject()  {}
+func (PDFName) isPDFObject()   {}
+func (PDFString) isPDFObject() {}
+func (PDFInt) isPDFObject()    {}
+func (PDFReal) isPDFObject()   {}
+func (PDFBool) isPDFObject()   {}
+func (PDFNull) isPDFObject()   {}
+func (PDFRef) isPDFObject()    {}
+func (PDFStream) isPDFObject() {}
+
+// ParseObject parses a PDF object. b may be a bare object or an indirect object
+// as returned by readEntry (N G obj ... endobj).
+func ParseObject(b []byte) (PDFObject, error) {
+	p := newObjectParser(NewLexer(bytes.NewReader(b)))
+
+	if _, _, err := p.readObjectHeader(); err != nil {
+		// not an indirect object
+		p.reset()
+	}
+
+	obj, err := p.parseObject()
+	if err != nil {
+		return nil, err
+	}
+
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if tok.Is(TokenKeyword, "stream") {
+		dict, ok := obj.(PDFDict)
+		if !ok {
+			return nil, errors.New("stream must be preceded by a dictionary")
+		}
+		return PDFStream{Dict: dict}, nil
+	}
+
+	return obj, nil
+}
+
+type objectParser struct {
+	lex *Lexer
+
+	// read holds tokens read since the last reset or commit for backtracking
+	read []Token
+	// unread holds tokens pushed back to be returned by next
+	unread []Token
+	// err is the error of the lexer which is returned again after backtracking
+	err error
+}
+
+func newObjectParser(lex *Lexer) *objectParser {
+	return &objectParser{lex: lex}
+}
+
+func (p *objectParser) next() (Token, error) {
+	var tok Token
+	if n := len(p.unread); n > 0 {
+		tok = p.unread[n-1]
+		p.unread = p.unread[:n-1]
+	} else {
+		if p.err != nil {
+			return Token{}, p.err
+		}
+		var err error
+		tok, err = p.lex.Next()
+		if err != nil {
+			p.err = err
+			return tok, err
+		}
+	}
+	p.read = append(p.read, tok)
+	return tok, nil
+}
+
+// reset pushes back all tokens read since the last commit.
+func (p *objectParser) reset() {
+	for i := len(p.read) - 1; i >= 0; i-- {
+		p.unread = append(p.unread, p.read[i])
+	}
+	p.read = p.read[:0]
+}
+
+// commit forgets tokens read so far.
+func (p *objectParser) commit() {
+	p.read = p.read[:0]
+}
+
+// back pushes back the last n tokens.
+func (p *objectParser) back(n int) {
+	for i := 0; i < n; i++ {
+		last := len(p.read) - 1
+		p.unread = append(p.unread, p.read[last])
+		p.read = p.read[:last]
+	}
+}
+
+// readObjectHeader reads "N G obj".
+func (p *objectParser) readObjectHeader() (int64, int, error) {
+	var nums [2]int64
+	for i := range nums {
+		tok, err := p.next()
+		if err != nil {
+			return 0, 0, err
+		}
+		if tok.Kind != TokenInteger {
+			return 0, 0, fmt.Errorf("expected an integer in object header but got %s %q", tok.Kind, tok.Value)
+		}
+		nums[i], _ = strconv.ParseInt(tok.Value, 10, 64)
+	}
+
+	tok, err := p.next()
+	if err != nil {
+		return 0, 0, err
+	}
+	if !tok.Is(TokenKeyword, "obj") {
+		return 0, 0, fmt.Errorf("expected obj but got %s %q", tok.Kind, tok.Value)
+	}
+
+	p.commit()
+	return nums[0], int(nums[1]), nil
+}
+
+func (p *objectParser) parseObject() (PDFObject, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := p.parseToken(tok)
+	if err != nil {
+		return nil, err
+	}
+	p.commit()
+	return obj, nil
+}
+
+func (p *objectParser) parseToken(tok Token) (PDFObject, error) {
+	switch tok.Kind {
+	case TokenEOF:
+		return nil, errors.New("unexpected end of object")
+	case TokenName:
+		return PDFName(tok.Value), nil
+	case TokenString, TokenHexString:
+		return PDFString(tok.Value), nil
+	case TokenDictBegin:
+		return p.parseDict()
+	case TokenArrayBegin:
+		return p.parseArray()
+	case TokenInteger:
+		return p.parseIntegerOrRef(tok)
+	case TokenReal:
+		f, err := parseReal(tok.Value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse real: %w", err)
+		}
+		return PDFReal(f), nil
+	case TokenKeyword:
+		switch tok.Value {
+		case "true":
+			return PDFBool(true), nil
+		case "false":
+			return PDFBool(false), nil
+		case "null":
+			return PDFNull{}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected %s %q", tok.Kind, tok.Value)
+}
+
+func (p *objectParser) parseIntegerOrRef(tok Token) (PDFObject, error) {
+	n, err := strconv.ParseInt(tok.Value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse integer: %w", err)
+	}
+
+	// lookahead for a reference (N G R)
+	gen, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if gen.Kind != TokenInteger {
+		p.back(1)
+		return PDFInt(n), nil
+	}
+
+	r, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if !r.Is(TokenKeyword, "R") {
+		p.back(2)
+		return PDFInt(n), nil
+	}
+
+	generation, err := strconv.Atoi(gen.Value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse generation: %w", err)
+	}
+	return PDFRef{Number: n, Generation: generation}, nil
+}
+
+func (p *objectParser) parseDict() (PDFObject, error) {
+	dict := PDFDict{}
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok.Kind {
+		case TokenDictEnd:
+			return dict, nil
+		case TokenEOF:
+			return nil, errors.New("unterminated dictionary")
+		case TokenName:
+		default:
+			return nil, fmt.Errorf("dictionary key must be a name but got %s %q", tok.Kind, tok.Value)
+		}
+
+		vtok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.parseToken(vtok)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the value of /%s: %w", tok.Value, err)
+		}
+		dict[tok.Value] = value
+	}
+}
+
+func (p *objectParser) parseArray() (PDFObject, error) {
+	arr := PDFArray{}
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok.Kind {
+		case TokenArrayEnd:
+			return arr, nil
+		case TokenEOF:
+			return nil, errors.New("unterminated array")
+		}
+
+		obj, err := p.parseToken(tok)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, obj)
+	}
+}
@@ -0,0 +1,37 @@
+package pdf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// ObjectHashes returns SHA-256 in hex of every in-use object keyed by the object number.
+// An object is hashed in the syntax rendered by String so that the layout in the file does not matter.
+// A stream is hashed with its raw body which is decrypted if the document is decrypted.
+func (d *Document) ObjectHashes() (map[int64]string, error) {
+	hashes := map[int64]string{}
+	err := d.Objects(func(number int64, generation int, obj PDFObject) error {
+		h := sha256.New()
+		fmt.Fprint(h, renderObject(obj))
+
+		if stream, ok := obj.(PDFStream); ok {
+			ent, err := d.XrefEntry(number, generation)
+			if err != nil {
+				return err
+			}
+			raw, err := d.ReadStreamBody(ent, stream.Dict)
+			if err != nil {
+				return fmt.Errorf("unable to read %d %d R: %w", number, generation, err)
+			}
+			h.Write(raw)
+		}
+
+		hashes[number] = hex.EncodeToString(h.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
@@ -0,0 +1,141 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// 7.5.7 Object Streams
+type objectStream struct {
+	numbers []int64
+	offsets []int64
+	// data holds the decoded stream after /First
+	data []byte
+}
+
+func (d *Document) readCompressedObject(objStmNum int64, index int) (PDFObject, error) {
+	stm, err := d.objectStream(objStmNum)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 || index >= len(stm.offsets) {
+		return nil, fmt.Errorf("index %d is out of range in object stream %d", index, objStmNum)
+	}
+
+	start := stm.offsets[index]
+	end := int64(len(stm.data))
+	if index+1 < len(stm.offsets) && stm.offsets[index+1] > start {
+		end = stm.offsets[index+1]
+	}
+	if start > end {
+		return nil, fmt.Errorf("offset %d is out of range in object stream %d", start, objStmNum)
+	}
+
+	obj, err := ParseObject(stm.data[start:end])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse object %d in object stream %d: %w", stm.numbers[index], objStmNum, err)
+	}
+	return obj, nil
+}
+
+// objectStream returns the decoded object stream. It is cached once decoded.
+func (d *Document) objectStream(objStmNum int64) (*objectStream, error) {
+	if stm, ok := d.objStmCache[objStmNum]; ok {
+		return stm, nil
+	}
+
+	// an object stream in an object stream, including itself, would be decoded recursively without end
+	ent, err := d.XrefEntry(objStmNum, 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find object stream %d: %w", objStmNum, err)
+	}
+	if ent.Compressed {
+		return nil, fmt.Errorf("object stream %d must not be in object stream %d", objStmNum, ent.StreamNumber)
+	}
+	if d.objStmLoading[objStmNum] {
+		return nil, fmt.Errorf("object stream %d is referenced while it is decoded", objStmNum)
+	}
+	if d.objStmLoading == nil {
+		d.objStmLoading = map[int64]bool{}
+	}
+	d.objStmLoading[objStmNum] = true
+	defer delete(d.objStmLoading, objStmNum)
+
+	ref := PDFRef{Number: objStmNum}
+	obj, err := d.Resolve(ref)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve object stream %d: %w", objStmNum, err)
+	}
+	stream, ok := obj.(PDFStream)
+	if !ok {
+		return nil, fmt.Errorf("object stream %d must be a stream but got %T", objStmNum, obj)
+	}
+	if typ, _ := stream.Dict["Type"].(PDFName); typ != "ObjStm" {
+		return nil, fmt.Errorf("object stream %d must have /Type /ObjStm but got %q", objStmNum, typ)
+	}
+
+	n, ok := stream.Dict["N"].(PDFInt)
+	if !ok || n < 0 {
+		return nil, fmt.Errorf("object stream %d must have /N", objStmNum)
+	}
+	first, ok := stream.Dict["First"].(PDFInt)
+	if !ok || first < 0 {
+		return nil, fmt.Errorf("object stream %d must have /First", objStmNum)
+	}
+
+	raw, err := d.ReadStreamBody(ent, stream.Dict)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read object stream %d: %w", objStmNum, err)
+	}
+	data, err := DecodeStreamWithLimit(stream.Dict, raw, d.decodedSizeLimit())
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode object stream %d: %w", objStmNum, err)
+	}
+	if int64(first) > int64(len(data)) {
+		return nil, fmt.Errorf("object stream %d is shorter than /First", objStmNum)
+	}
+
+	// N pairs of integers of the object number and the offset relative to /First
+	stm := &objectStream{data: data[first:]}
+	lex := NewLexer(bytes.NewReader(data[:first]))
+	for i := 0; i < int(n); i++ {
+		var pair [2]int64
+		for j := range pair {
+			tok, err := lex.Next()
+			if err != nil {
+				return nil, err
+			}
+			if tok.Kind != TokenInteger {
+				return nil, errors.New("object stream header must have pairs of integers")
+			}
+			pair[j], _ = strconv.ParseInt(tok.Value, 10, 64)
+		}
+		stm.numbers = append(stm.numbers, pair[0])
+		stm.offsets = append(stm.offsets, pair[1])
+	}
+
+	d.objStmCache[objStmNum] = stm
+	return stm, nil
+}
+
+// ObjStmContents decodes the object stream objStmNum and returns all objects in it keyed by the object number.
+// The objects are returned as stored even if newer revisions override them.
+func (d *Document) ObjStmContents(objStmNum int64) (map[int64]PDFObject, error) {
+	stm, err := d.objectStream(objStmNum)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := make(map[int64]PDFObject, len(stm.numbers))
+	for i, number := range stm.numbers {
+		obj, err := d.readCompressedObject(objStmNum, i)
+		if err != nil {
+			return nil, err
+		}
+		objs[number] = obj
+	}
+	return objs, nil
+}
@@ -0,0 +1,58 @@
+package pdf
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestReadCompressedObject(t *testing.T) {
+	data := "3 0 << /Type /Pages /Kids [] /Count 0 >>"
+	b := buildXrefStreamPDF(map[int64]string{
+		1: "<< /Type /Catalog /Pages 3 0 R >>",
+		2: fmt.Sprintf("<< /Type /ObjStm /N 1 /First 4 /Length %d >>\nstream\n%s\nendstream", len(data), data),
+	}, map[int64][2]int64{3: {2, 0}})
+	d := openBytes(t, b)
+
+	obj, err := d.Resolve(PDFRef{Number: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict, ok := obj.(PDFDict); !ok || dict["Type"] != PDFName("Pages") {
+		t.Errorf("got %v", obj)
+	}
+}
+
+func TestReadCompressedObjectRecursive(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		pdf  []byte
+		ref  PDFRef
+	}{
+		{
+			// the object stream is in itself
+			name: "self",
+			pdf:  buildXrefStreamPDF(map[int64]string{1: "<< /Type /Catalog >>"}, map[int64][2]int64{2: {2, 0}}),
+			ref:  PDFRef{Number: 2},
+		},
+		{
+			// two object streams are in each other
+			name: "each other",
+			pdf:  buildXrefStreamPDF(map[int64]string{1: "<< /Type /Catalog >>"}, map[int64][2]int64{2: {4, 0}, 4: {2, 0}}),
+			ref:  PDFRef{Number: 2},
+		},
+		{
+			// /Length of the object stream is in the object stream
+			name: "length",
+			pdf: buildXrefStreamPDF(map[int64]string{
+				1: "<< /Type /Catalog >>",
+				2: "<< /Type /ObjStm /N 1 /First 4 /Length 3 0 R >>\nstream\n3 0 10\nendstream",
+			}, map[int64][2]int64{3: {2, 0}}),
+			ref: PDFRef{Number: 3},
+		},
+	} {
+		d := openBytes(t, tc.pdf)
+		if obj, err := d.Resolve(tc.ref); err == nil {
+			t.Errorf("%s: %v must not be resolved but got %v", tc.name, tc.ref, obj)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package pdf
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Orphans returns the numbers of in-use objects which are not reachable from /Root, /Info or /Encrypt of the trailer.
+// An object stream is reachable when any object in it is reachable. Cross-reference streams and
+// the linearization parameter dictionary are not reported since they are found by offsets.
+func (d *Document) Orphans() ([]int64, error) {
+	_, reached := d.reachable([]string{"Root", "Info", "Encrypt"}, true)
+
+	var orphans []int64
+	for _, ent := range d.entries {
+		if !ent.InUse || reached[ent.Number] || ent.Number == 0 {
+			continue
+		}
+
+		obj, err := d.readObject(PDFRef{Number: ent.Number, Generation: ent.Generation})
+		if err == nil {
+			var dict PDFDict
+			switch obj := obj.(type) {
+			case PDFDict:
+				dict = obj
+			case PDFStream:
+				dict = obj.Dict
+			}
+			if typ, _ := dict["Type"].(PDFName); typ == "XRef" {
+				continue
+			}
+			if _, ok := dict["Linearized"]; ok {
+				continue
+			}
+		}
+		orphans = append(orphans, ent.Number)
+	}
+
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i] < orphans[j] })
+	return orphans, nil
+}
+
+// reachable follows references from the keys of the trailer dictionary and returns the references in the order found
+// and the object numbers reached including the numbers of object streams containing them.
+// References in /Length of streams are not followed if followLength is false.
+func (d *Document) reachable(keys []string, followLength bool) ([]PDFRef, map[int64]bool) {
+	var refs []PDFRef
+	reached := map[int64]bool{}
+
+	var queue []PDFRef
+	for _, key := range keys {
+		collectRefs(d.trailerDict[key], func(ref PDFRef) {
+			queue = append(queue, ref)
+		})
+	}
+
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if reached[ref.Number] {
+			continue
+		}
+		reached[ref.Number] = true
+		refs = append(refs, ref)
+
+		if ent, err := d.XrefEntry(ref.Number, ref.Generation); err == nil && ent.Compressed {
+			reached[ent.StreamNumber] = true
+		}
+
+		// an unreadable object has no references to follow
+		obj, err := d.readObject(ref)
+		if err != nil {
+			continue
+		}
+		if stream, ok := obj.(PDFStream); ok && !followLength {
+			dict := PDFDict{}
+			for k, v := range stream.Dict {
+				if k != "Length" {
+					dict[k] = v
+				}
+			}
+			obj = dict
+		}
+		collectRefs(obj, func(ref PDFRef) {
+			if !reached[ref.Number] {
+				queue = append(queue, ref)
+			}
+		})
+	}
+
+	return refs, reached
+}
+
+// collectRefs calls fn for every reference in obj including ones nested in arrays, dictionaries
+// and stream dictionaries. References are not resolved.
+func collectRefs(obj PDFObject, fn func(ref PDFRef)) {
+	walkRefs(obj, "", func(_ string, ref PDFRef) { fn(ref) })
+}
+
+// walkRefs is collectRefs with the path to each reference from obj such as /Kids[0].
+// Keys of dictionaries are visited in the sorted order.
+func walkRefs(obj PDFObject, path string, fn func(path string, ref PDFRef)) {
+	switch obj := obj.(type) {
+	case PDFRef:
+		fn(path, obj)
+	case PDFArray:
+		for i, elem := range obj {
+			walkRefs(elem, fmt.Sprintf("%s[%d]", path, i), fn)
+		}
+	case PDFDict:
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			walkRefs(obj[k], path+"/"+k, fn)
+		}
+	case PDFStream:
+		walkRefs(obj.Dict, path, fn)
+	}
+}
@@ -0,0 +1,115 @@
+package pdf
+
+import (
+	"fmt"
+)
+
+// 12.3.3 Document Outline
+type OutlineNode struct {
+	Title string
+	// Dest is the destination (/Dest) or nil
+	Dest PDFObject
+	// Action is the action (/A) or nil
+	Action PDFDict
+	// Count is /Count. It is negative when the node is closed.
+	Count int
+
+	Children []*OutlineNode
+}
+
+// Outlines returns the outline tree. The root node is the outline dictionary and has no title.
+// It returns nil without an error when the document has no outline.
+func (d *Document) Outlines() (*OutlineNode, error) {
+	catalog, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := catalog["Outlines"]; !ok {
+		return nil, nil
+	}
+
+	visited := map[PDFRef]bool{}
+	if ref, ok := catalog["Outlines"].(PDFRef); ok {
+		visited[ref] = true
+	}
+	dict, err := d.resolveDict(catalog["Outlines"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /Outlines: %w", err)
+	}
+
+	root := &OutlineNode{}
+	if count, ok := dict["Count"].(PDFInt); ok {
+		root.Count = int(count)
+	}
+	if err := d.readOutlineChildren(root, dict, visited, 1); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// readOutlineChildren follows /First and /Next of parent. depth is the level of the children.
+func (d *Document) readOutlineChildren(node *OutlineNode, parent PDFDict, visited map[PDFRef]bool, depth int) error {
+	next := parent["First"]
+	for next != nil {
+		ref, ok := next.(PDFRef)
+		if !ok {
+			return fmt.Errorf("outline item must be an indirect reference but got %T", next)
+		}
+		if visited[ref] {
+			return fmt.Errorf("cyclic outline at %d %d R", ref.Number, ref.Generation)
+		}
+		visited[ref] = true
+		if err := d.checkDepth(depth, ref); err != nil {
+			return err
+		}
+
+		item, err := d.resolveDict(ref)
+		if err != nil {
+			return fmt.Errorf("unable to resolve outline item %d %d R: %w", ref.Number, ref.Generation, err)
+		}
+
+		child, err := d.outlineItem(item)
+		if err != nil {
+			return fmt.Errorf("unable to read outline item %d %d R: %w", ref.Number, ref.Generation, err)
+		}
+		if err := d.readOutlineChildren(child, item, visited, depth+1); err != nil {
+			return err
+		}
+		node.Children = append(node.Children, child)
+
+		next = item["Next"]
+	}
+	return nil
+}
+
+// Table 153 Entries in an outline item dictionary
+func (d *Document) outlineItem(item PDFDict) (*OutlineNode, error) {
+	node := &OutlineNode{}
+
+	title, err := d.Resolve(item["Title"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /Title: %w", err)
+	}
+	if s, ok := title.(PDFString); ok {
+		node.Title = DecodeTextString(s)
+	}
+
+	if count, ok := item["Count"].(PDFInt); ok {
+		node.Count = int(count)
+	}
+
+	if dest, ok := item["Dest"]; ok {
+		node.Dest, err = d.Resolve(dest)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve /Dest: %w", err)
+		}
+	}
+	if _, ok := item["A"]; ok {
+		node.Action, err = d.resolveDict(item["A"])
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve /A: %w", err)
+		}
+	}
+
+	return node, nil
+}
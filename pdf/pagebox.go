@@ -0,0 +1,143 @@
+package pdf
+
+import (
+	"fmt"
+)
+
+// Rectangle is a rectangle in default user space.
+// 7.9.5 Rectangles
+type Rectangle struct {
+	LLX, LLY, URX, URY float64
+
+	// Rotate is /Rotate of the page in degrees (0, 90, 180 or 270) when returned by PageBox
+	Rotate int
+}
+
+// Width returns the width of the rectangle as displayed, that is, the height when rotated by 90 or 270 degrees.
+func (r Rectangle) Width() float64 {
+	if r.Rotate == 90 || r.Rotate == 270 {
+		return r.URY - r.LLY
+	}
+	return r.URX - r.LLX
+}
+
+// Height returns the height of the rectangle as displayed.
+func (r Rectangle) Height() float64 {
+	if r.Rotate == 90 || r.Rotate == 270 {
+		return r.URX - r.LLX
+	}
+	return r.URY - r.LLY
+}
+
+// PageBox returns the page boundary box of page which is MediaBox, CropBox, BleedBox, TrimBox or ArtBox.
+// page must be one returned by Pages so that inherited attributes are available.
+// 14.11.2 Page Boundaries
+// CropBox defaults to MediaBox and the others default to CropBox.
+func (d *Document) PageBox(page PDFDict, box string) (Rectangle, error) {
+	var fallback string
+	switch box {
+	case "MediaBox":
+	case "CropBox":
+		fallback = "MediaBox"
+	case "BleedBox", "TrimBox", "ArtBox":
+		fallback = "CropBox"
+	default:
+		return Rectangle{}, fmt.Errorf("unknown page box: %s", box)
+	}
+
+	obj, ok := page[box]
+	if !ok {
+		if fallback == "" {
+			return Rectangle{}, fmt.Errorf("page must have /%s", box)
+		}
+		return d.PageBox(page, fallback)
+	}
+
+	rect, err := d.rectangle(obj)
+	if err != nil {
+		return Rectangle{}, fmt.Errorf("unable to read /%s: %w", box, err)
+	}
+
+	rect.Rotate, err = d.PageRotation(page)
+	if err != nil {
+		return Rectangle{}, err
+	}
+	return rect, nil
+}
+
+// PageRotation returns /Rotate of page normalized to 0, 90, 180 or 270 such as 270 for -90.
+// /Rotate is inherited from the ancestors through /Parent when page does not have it. It defaults to 0.
+// 7.7.3.3 /Rotate must be a multiple of 90
+func (d *Document) PageRotation(page PDFDict) (int, error) {
+	node := page
+	visited := map[PDFRef]bool{}
+	for depth := 1; ; depth++ {
+		if obj, ok := node["Rotate"]; ok {
+			resolved, err := d.Resolve(obj)
+			if err != nil {
+				return 0, fmt.Errorf("unable to resolve /Rotate: %w", err)
+			}
+			rotate, ok := resolved.(PDFInt)
+			if !ok {
+				return 0, fmt.Errorf("/Rotate must be an integer but got %s", renderObject(resolved))
+			}
+			if rotate%90 != 0 {
+				return 0, fmt.Errorf("/Rotate must be a multiple of 90 but got %d", rotate)
+			}
+			return (int(rotate)%360 + 360) % 360, nil
+		}
+
+		parent, ok := node["Parent"].(PDFRef)
+		if !ok {
+			return 0, nil
+		}
+		if err := d.checkDepth(depth, parent); err != nil {
+			return 0, err
+		}
+		if visited[parent] {
+			return 0, fmt.Errorf("cyclic /Parent at %d %d R", parent.Number, parent.Generation)
+		}
+		visited[parent] = true
+
+		var err error
+		node, err = d.resolveDict(parent)
+		if err != nil {
+			return 0, fmt.Errorf("unable to resolve /Parent: %w", err)
+		}
+	}
+}
+
+// rectangle reads an array of four numbers as a rectangle normalized to the lower-left and upper-right corners.
+func (d *Document) rectangle(obj PDFObject) (Rectangle, error) {
+	resolved, err := d.Resolve(obj)
+	if err != nil {
+		return Rectangle{}, err
+	}
+	arr, ok := resolved.(PDFArray)
+	if !ok || len(arr) != 4 {
+		return Rectangle{}, fmt.Errorf("rectangle must be an array of four numbers but got %s", renderObject(resolved))
+	}
+
+	var v [4]float64
+	for i, elem := range arr {
+		elem, err := d.Resolve(elem)
+		if err != nil {
+			return Rectangle{}, err
+		}
+		f, ok := toFloat(elem)
+		if !ok {
+			return Rectangle{}, fmt.Errorf("rectangle must be an array of four numbers but got %s", renderObject(resolved))
+		}
+		v[i] = f
+	}
+
+	// any two diagonally opposite corners may be given
+	rect := Rectangle{LLX: v[0], LLY: v[1], URX: v[2], URY: v[3]}
+	if rect.LLX > rect.URX {
+		rect.LLX, rect.URX = rect.URX, rect.LLX
+	}
+	if rect.LLY > rect.URY {
+		rect.LLY, rect.URY = rect.URY, rect.LLY
+	}
+	return rect, nil
+}
@@ -0,0 +1,132 @@
+package pdf
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PageLabels returns the label of each page in /PageLabels of the catalog.
+// It returns nil without an error when the document has no page labels.
+// A page before the first range has an empty label.
+// 12.4.2 Page Labels
+func (d *Document) PageLabels() ([]string, error) {
+	catalog, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := catalog["PageLabels"]; !ok {
+		return nil, nil
+	}
+	root, err := d.resolveDict(catalog["PageLabels"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve /PageLabels: %w", err)
+	}
+
+	type labelRange struct {
+		start int64
+		dict  PDFDict
+	}
+	var ranges []labelRange
+	err = d.WalkNumberTree(root, func(key int64, val PDFObject) error {
+		dict, err := d.resolveDict(val)
+		if err != nil {
+			return fmt.Errorf("unable to resolve the page label at %d: %w", key, err)
+		}
+		ranges = append(ranges, labelRange{start: key, dict: dict})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// keys must be sorted but a broken tree is tolerated
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].start < ranges[j].start })
+
+	pages, err := d.Pages()
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]string, len(pages))
+	for i, r := range ranges {
+		end := int64(len(pages))
+		if i+1 < len(ranges) && ranges[i+1].start < end {
+			end = ranges[i+1].start
+		}
+		if r.start < 0 || r.start >= end {
+			continue
+		}
+
+		// Table 159 Entries in a page label dictionary
+		style, _ := r.dict["S"].(PDFName)
+		var prefix string
+		if obj, err := d.Resolve(r.dict["P"]); err == nil {
+			s, _ := obj.(PDFString)
+			prefix = DecodeTextString(s)
+		}
+		first := int64(1)
+		if st, ok := r.dict["St"].(PDFInt); ok && st >= 1 {
+			first = int64(st)
+		}
+
+		for page := r.start; page < end; page++ {
+			labels[page] = prefix + formatPageNumber(style, first+page-r.start)
+		}
+	}
+	return labels, nil
+}
+
+// maxLetterNumeral is the largest number written in roman numerals or letters.
+// A larger /St is written in decimal so that a label does not grow with the number.
+const maxLetterNumeral = 10000
+
+// formatPageNumber returns n in the numbering style of a page label. An empty style has no number.
+func formatPageNumber(style PDFName, n int64) string {
+	switch style {
+	case "D", "R", "r", "A", "a":
+	default:
+		return ""
+	}
+	if style == "D" || n > maxLetterNumeral {
+		return strconv.FormatInt(n, 10)
+	}
+
+	letters := alphaNumeral(n)
+	if style == "R" || style == "r" {
+		letters = romanNumeral(n)
+	}
+	if style == "r" || style == "a" {
+		return strings.ToLower(letters)
+	}
+	return letters
+}
+
+// romanNumeral returns n in upper-case roman numerals. Thousands are repeated M.
+func romanNumeral(n int64) string {
+	numerals := []struct {
+		value  int64
+		symbol string
+	}{
+		{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+		{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+		{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+	}
+	var b strings.Builder
+	for _, num := range numerals {
+		for n >= num.value {
+			b.WriteString(num.symbol)
+			n -= num.value
+		}
+	}
+	return b.String()
+}
+
+// alphaNumeral returns n in upper-case letters as A to Z for 1 to 26, AA to ZZ for 27 to 52 and so on.
+func alphaNumeral(n int64) string {
+	if n < 1 {
+		return ""
+	}
+	letter := string(rune('A' + (n-1)%26))
+	return strings.Repeat(letter, int((n-1)/26)+1)
+}
@@ -0,0 +1,66 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsePageRange returns the page numbers (the first page is 1) selected by spec in the order of spec.
+// spec is comma-separated pages or ranges such as "1-3,5,8-" where "8-" is the 8th page to the last one.
+// A negative page counts from the end so that -1 is the last page and "-3--1" is the last 3 pages.
+// total is the number of pages.
+func ParsePageRange(spec string, total int) ([]int, error) {
+	page := func(s string) (int, error) {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid page %q", s)
+		}
+		if n < 0 {
+			n += total + 1
+		}
+		if n < 1 || n > total {
+			return 0, fmt.Errorf("page %s is out of range (1-%d)", s, total)
+		}
+		return n, nil
+	}
+
+	var pages []int
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+
+		// the separator is a hyphen after the first character which may be a minus sign
+		sep := -1
+		if len(item) > 1 {
+			if i := strings.Index(item[1:], "-"); i >= 0 {
+				sep = i + 1
+			}
+		}
+		if sep < 0 {
+			n, err := page(item)
+			if err != nil {
+				return nil, err
+			}
+			pages = append(pages, n)
+			continue
+		}
+
+		first, err := page(item[:sep])
+		if err != nil {
+			return nil, err
+		}
+		last := total
+		if end := item[sep+1:]; end != "" {
+			if last, err = page(end); err != nil {
+				return nil, err
+			}
+		}
+		if first > last {
+			return nil, fmt.Errorf("invalid page range %q", item)
+		}
+		for n := first; n <= last; n++ {
+			pages = append(pages, n)
+		}
+	}
+	return pages, nil
+}
@@ -0,0 +1,138 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Table 31 Inheritable page attributes
+var inheritablePageAttributes = []string{"Resources", "MediaBox", "CropBox", "Rotate"}
+
+// Pages returns page dictionaries in the document order.
+// Inheritable attributes of ancestor nodes are copied into each page dictionary.
+func (d *Document) Pages() ([]PDFDict, error) {
+	return d.PagesContext(context.Background())
+}
+
+// PagesContext is the same as Pages but aborts walking the page tree when ctx is done.
+func (d *Document) PagesContext(ctx context.Context) ([]PDFDict, error) {
+	catalog, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := catalog["Pages"]
+	if !ok {
+		return nil, errors.New("catalog must have /Pages")
+	}
+
+	var pages []PDFDict
+	if err := d.walkPageTree(ctx, root, PDFDict{}, map[PDFRef]bool{}, &pages, nil, 1); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// pageRefs returns references to page objects in the document order.
+// A page which is a direct object has the zero PDFRef.
+func (d *Document) pageRefs() ([]PDFRef, error) {
+	catalog, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := catalog["Pages"]
+	if !ok {
+		return nil, errors.New("catalog must have /Pages")
+	}
+
+	var pages []PDFDict
+	var refs []PDFRef
+	if err := d.walkPageTree(context.Background(), root, PDFDict{}, map[PDFRef]bool{}, &pages, &refs, 1); err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// 7.7.3 Page Tree
+// refs receives references to pages if not nil. depth is 1 at the root.
+func (d *Document) walkPageTree(ctx context.Context, obj PDFObject, inherited PDFDict, visited map[PDFRef]bool, pages *[]PDFDict, refs *[]PDFRef, depth int) error {
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+	if err := d.checkDepth(depth, obj); err != nil {
+		return err
+	}
+
+	if ref, ok := obj.(PDFRef); ok {
+		if visited[ref] {
+			return fmt.Errorf("cyclic page tree at %d %d R", ref.Number, ref.Generation)
+		}
+		visited[ref] = true
+	}
+
+	resolved, err := d.ResolveContext(ctx, obj)
+	if err != nil {
+		return fmt.Errorf("unable to resolve a page tree node: %w", err)
+	}
+	node, ok := resolved.(PDFDict)
+	if !ok {
+		return fmt.Errorf("page tree node must be a dictionary but got %T", resolved)
+	}
+
+	typ, _ := node["Type"].(PDFName)
+	if typ == "" {
+		// tolerate a missing /Type
+		if _, ok := node["Kids"]; ok {
+			typ = "Pages"
+		} else {
+			typ = "Page"
+		}
+	}
+
+	switch typ {
+	case "Pages":
+		attrs := PDFDict{}
+		for k, v := range inherited {
+			attrs[k] = v
+		}
+		for _, k := range inheritablePageAttributes {
+			if v, ok := node[k]; ok {
+				attrs[k] = v
+			}
+		}
+
+		kidsObj, err := d.Resolve(node["Kids"])
+		if err != nil {
+			return fmt.Errorf("unable to resolve /Kids: %w", err)
+		}
+		kids, ok := kidsObj.(PDFArray)
+		if !ok {
+			return fmt.Errorf("/Kids must be an array but got %T", kidsObj)
+		}
+
+		for _, kid := range kids {
+			if err := d.walkPageTree(ctx, kid, attrs, visited, pages, refs, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "Page":
+		page := PDFDict{}
+		for k, v := range inherited {
+			page[k] = v
+		}
+		for k, v := range node {
+			page[k] = v
+		}
+		*pages = append(*pages, page)
+		if refs != nil {
+			ref, _ := obj.(PDFRef)
+			*refs = append(*refs, ref)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unexpected page tree node type: %s", typ)
+}
@@ -0,0 +1,714 @@
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrBadStartxref is returned when startxref is missing or is not a byte offset.
+	ErrBadStartxref = errors.New("bad startxref")
+	// ErrNotXref is returned when neither a cross-reference table nor a cross-reference stream is at the offset.
+	ErrNotXref = errors.New("should be xref")
+)
+
+// 7.5.4 Cross-Reference Table
+type XrefEntry struct {
+	ByteOffset int64
+	Number     int64
+	Generation int
+	InUse      bool
+
+	// 7.5.8.3 Compressed objects live in an object stream
+	Compressed   bool
+	StreamNumber int64
+	StreamIndex  int
+}
+
+// 7.5.5 Trailer
+// trailer
+// ...
+// %%EOF
+type Trailer struct {
+	StartXref int64
+	// Size is /Size in Dict. It is 0 when unknown.
+	Size int64
+	Raw  []byte
+
+	// Dict is the trailer dictionary following the trailer keyword or the dictionary of the cross-reference
+	// stream at startxref which carries the same entries. It is nil when the stream cannot be read at StartXref.
+	Dict PDFDict
+
+	// Strict rejects a cross-reference table entry which is not exactly 20 bytes long
+	// instead of realigning on the next entry.
+	Strict bool
+
+	ra io.ReaderAt
+	// size is the size of the file
+	size int64
+	// base is added to offsets in cross-reference sections when there are bytes before the header.
+	// StartXref already includes it.
+	base int64
+}
+
+// maxLineLength is the maximum length of a line read by newLineScanner.
+// A minified dictionary or a binary stream body can be a single line much longer than bufio.MaxScanTokenSize.
+const maxLineLength = 1 << 30
+
+// newLineScanner returns a line scanner which grows its buffer up to maxLineLength as needed.
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	return scanner
+}
+
+// readEntry returns the bytes of the object at ent from "N G obj" through "endobj".
+func readEntry(ent XrefEntry, ra io.ReaderAt) ([]byte, error) {
+	end, err := entryEnd(ra, ent.ByteOffset, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read entry: %w", err)
+	}
+
+	b := make([]byte, end-ent.ByteOffset)
+	if _, err := ra.ReadAt(b, ent.ByteOffset); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("unable to read entry: %w", err)
+	}
+	return b, nil
+}
+
+// entryEnd returns the offset just after endobj of the object at offset.
+// The tokens are read with the lexer so that the object may share lines with obj and endobj.
+// resolve resolves an indirect /Length of a stream. Without it, the body is delimited by endstream.
+func entryEnd(ra io.ReaderAt, offset int64, resolve func(PDFObject) (PDFObject, error)) (int64, error) {
+	lex := NewLexer(NewAtReader(ra, offset))
+	p := newObjectParser(lex)
+	if _, _, err := p.readObjectHeader(); err != nil {
+		return 0, fmt.Errorf("should start with a reference object: %w", err)
+	}
+	if _, err := p.parseObject(); err != nil {
+		return 0, fmt.Errorf("unable to parse object: %w", err)
+	}
+
+	tok, err := p.next()
+	if err != nil {
+		return 0, err
+	}
+	if tok.Is(TokenKeyword, "endobj") {
+		return offset + lex.Offset(), nil
+	}
+	if !tok.Is(TokenKeyword, "stream") {
+		return 0, fmt.Errorf("object must end with endobj but got %s %q", tok.Kind, tok.Value)
+	}
+
+	// 7.3.8.1 the body is followed by endstream and endobj
+	dict, bodyOffset, err := readStreamHeader(ra, offset)
+	if err != nil {
+		return 0, err
+	}
+	length := int64(-1)
+	if obj, ok := dict["Length"]; ok {
+		if _, indirect := obj.(PDFRef); indirect && resolve != nil {
+			if obj, err = resolve(obj); err != nil {
+				return 0, fmt.Errorf("unable to resolve /Length: %w", err)
+			}
+		}
+		if n, ok := obj.(PDFInt); ok {
+			length = int64(n)
+		}
+	}
+	length, err = streamDataLength(ra, bodyOffset, length)
+	if err != nil {
+		return 0, err
+	}
+
+	lex = NewLexer(NewAtReader(ra, bodyOffset+length))
+	for _, keyword := range []string{"endstream", "endobj"} {
+		tok, err := lex.Next()
+		if err != nil {
+			return 0, err
+		}
+		if !tok.Is(TokenKeyword, keyword) {
+			return 0, fmt.Errorf("stream must end with %s but got %s %q", keyword, tok.Kind, tok.Value)
+		}
+	}
+	return bodyOffset + length + lex.Offset(), nil
+}
+
+// ReadTrailer reads the last trailer of the file.
+func ReadTrailer(ra io.ReaderAt, size int64) (Trailer, error) {
+	tr := Trailer{
+		ra:   ra,
+		size: size,
+	}
+
+	// grow the window until the last trailer is found
+	var buf []byte
+	for window := int64(1024); ; window *= 2 {
+		if window > size {
+			window = size
+		}
+
+		buf = make([]byte, window)
+		if _, err := ra.ReadAt(buf, size-window); err != nil && err != io.EOF {
+			return tr, err
+		}
+
+		if isTrailerInBlock(buf) {
+			break
+		}
+		if window == size {
+			// tolerate a missing %%EOF which Validate reports
+			if bytes.Contains(buf, []byte("startxref")) {
+				break
+			}
+			return tr, fmt.Errorf("%w: unable to find startxref", ErrBadStartxref)
+		}
+	}
+
+	hasTrailer := false
+	if p := findTrailerInBlock(buf); p >= 0 {
+		buf = buf[p:]
+		hasTrailer = true
+	}
+	tr.Raw = buf
+
+	scanner := newLineScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		l := scanner.Text()
+		if strings.HasPrefix(l, "startxref") {
+			scanner.Scan()
+			xref, err := strconv.ParseInt(scanner.Text(), 10, 64)
+			if err != nil {
+				return tr, fmt.Errorf("%w: unable to parse startxref: %v", ErrBadStartxref, err)
+			}
+			tr.StartXref = xref
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return tr, fmt.Errorf("unable to scan the trailer: %w", err)
+	}
+
+	if hasTrailer {
+		dict, err := readTrailerDict(bytes.NewReader(buf), 0)
+		if err != nil {
+			return tr, err
+		}
+		tr.Dict = dict
+	} else if dict, _, err := readStreamHeader(ra, tr.StartXref); err == nil {
+		// 7.5.8.2 the dictionary of a cross-reference stream is the trailer
+		tr.Dict = dict
+	}
+
+	// /Size is left 0 when it is unknown such as an indirect reference
+	if n, ok := tr.Dict["Size"].(PDFInt); ok {
+		if n <= 0 {
+			return tr, fmt.Errorf("invalid /Size: %d", n)
+		}
+		tr.Size = int64(n)
+	}
+
+	return tr, nil
+}
+
+// ListXrefEntries lists entries in the cross-reference section pointed by startxref.
+func (t Trailer) ListXrefEntries() ([]XrefEntry, error) {
+	entries, _, err := readXrefSection(t.ra, t.StartXref, t.Size, t.Strict)
+	return entries, err
+}
+
+// ResolveAllEntries lists entries in all cross-reference sections by following /Prev.
+// An entry in a newer section overrides any entry of the same object number in older sections
+// so a freed or reused object has only the newest entry.
+func (t Trailer) ResolveAllEntries() ([]XrefEntry, error) {
+	entries, _, err := t.resolveAllEntries(context.Background())
+	return entries, err
+}
+
+// resolveAllEntries is ResolveAllEntries and also returns the newest trailer dictionary.
+func (t Trailer) resolveAllEntries(ctx context.Context) ([]XrefEntry, PDFDict, error) {
+	revisions, err := t.revisions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged := map[int64]XrefEntry{}
+	// the newest revision comes last
+	for _, rev := range revisions {
+		for _, ent := range rev.Entries {
+			merged[ent.Number] = ent
+		}
+	}
+
+	entries := make([]XrefEntry, 0, len(merged))
+	for _, ent := range merged {
+		entries = append(entries, ent)
+	}
+	sortXrefEntries(entries)
+
+	return entries, revisions[len(revisions)-1].Trailer, nil
+}
+
+// Revision is a cross-reference section and its trailer dictionary added by an update.
+// 7.5.6 Incremental Updates
+type Revision struct {
+	// XrefOffset is the offset of the cross-reference section
+	XrefOffset int64
+	// Entries has the entries of the section including the ones in /XRefStm of a hybrid-reference file
+	Entries []XrefEntry
+	Trailer PDFDict
+}
+
+// revisions reads cross-reference sections by following /Prev and returns them from the oldest.
+func (t Trailer) revisions(ctx context.Context) ([]Revision, error) {
+	var revisions []Revision
+
+	visited := map[int64]bool{}
+	offset, size := t.StartXref, t.Size
+	for {
+		if err := contextErr(ctx); err != nil {
+			return nil, err
+		}
+		if visited[offset] {
+			return nil, fmt.Errorf("cyclic /Prev at %d", offset)
+		}
+		visited[offset] = true
+
+		entries, dict, err := readXrefSection(t.ra, offset, size, t.Strict)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read xref section at %d: %w", offset, err)
+		}
+
+		// 7.5.8.4 Compatibility with Applications That Do Not Support Compressed Reference Streams
+		// entries in the xref stream take precedence over the table in the same section
+		if xrefStm, ok := dict["XRefStm"].(PDFInt); ok {
+			streamEntries, _, err := listXrefStreamEntries(t.ra, int64(xrefStm)+t.base)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read /XRefStm at %d: %w", xrefStm, err)
+			}
+			entries = append(entries, streamEntries...)
+		}
+		if t.base != 0 {
+			for i := range entries {
+				if entries[i].InUse && !entries[i].Compressed {
+					entries[i].ByteOffset += t.base
+				}
+			}
+		}
+		revisions = append(revisions, Revision{XrefOffset: offset, Entries: entries, Trailer: dict})
+
+		prev, ok := dict["Prev"].(PDFInt)
+		if !ok {
+			break
+		}
+		offset, size = int64(prev)+t.base, 0
+	}
+
+	// the oldest comes first
+	for i, j := 0, len(revisions)-1; i < j; i, j = i+1, j-1 {
+		revisions[i], revisions[j] = revisions[j], revisions[i]
+	}
+	return revisions, nil
+}
+
+// readXrefSection reads a cross-reference section at offset and its trailer dictionary.
+// In strict mode, entries of a cross-reference table must be 20 bytes long.
+func readXrefSection(ra io.ReaderAt, offset, size int64, strict bool) ([]XrefEntry, PDFDict, error) {
+	l, next, err := readLineAt(ra, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+	if strings.TrimSpace(l) != "xref" {
+		if isObjectHeader(l) {
+			return listXrefStreamEntries(ra, offset)
+		}
+		return nil, nil, ErrNotXref
+	}
+
+	entries, end, err := listXrefTableEntries(ra, next, size, strict)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the trailer is searched from the end of the table instead of lexing all entries
+	dict, err := readTrailerDict(ra, end)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return entries, dict, nil
+}
+
+// readTrailerDict reads the trailer dictionary following the cross-reference table at offset.
+func readTrailerDict(ra io.ReaderAt, offset int64) (PDFDict, error) {
+	p := newObjectParser(NewLexer(NewAtReader(ra, offset)))
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return nil, fmt.Errorf("unable to find the trailer: %w", err)
+		}
+		if tok.Kind == TokenEOF {
+			return nil, errors.New("unable to find the trailer")
+		}
+		if tok.Is(TokenKeyword, "trailer") {
+			break
+		}
+	}
+
+	obj, err := p.parseObject()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the trailer: %w", err)
+	}
+	dict, ok := obj.(PDFDict)
+	if !ok {
+		return nil, errors.New("trailer must be a dictionary")
+	}
+	return dict, nil
+}
+
+// maxXrefSubsectionEntries limits the number of entries in a subsection to avoid a huge allocation.
+const maxXrefSubsectionEntries = 1 << 24
+
+// listXrefTableEntries lists entries in a cross-reference table at offset (after the xref keyword)
+// until the trailer keyword and returns them with the offset where the table ends.
+// It also stops when size entries are read if size is positive.
+// An entry which is not 20 bytes long is an error in strict mode. Otherwise the entry is read
+// as a line and the next entry is read at the end of the line.
+func listXrefTableEntries(ra io.ReaderAt, offset, size int64, strict bool) ([]XrefEntry, int64, error) {
+	var entries []XrefEntry
+	if size > 0 && size <= maxXrefSubsectionEntries {
+		entries = make([]XrefEntry, 0, size)
+	}
+	pos := offset
+	for size <= 0 || int64(len(entries)) < size {
+		l, next, err := readLineAt(ra, pos)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if strings.TrimSpace(l) == "" {
+			pos = next
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(l), "trailer") {
+			break
+		}
+		pos = next
+
+		// subsection header
+		header := strings.Fields(l)
+		if len(header) != 2 {
+			return nil, 0, fmt.Errorf("invalid xref subsection header %q", l)
+		}
+		start, err := strconv.ParseInt(header[0], 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to read xref subsection offset: %w", err)
+		}
+		count, err := strconv.Atoi(header[1])
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to read xref subsection count: %w", err)
+		}
+		if count < 0 || count > maxXrefSubsectionEntries {
+			return nil, 0, fmt.Errorf("invalid xref subsection count: %d", count)
+		}
+
+		// 7.5.4 each entry is exactly 20 bytes long including the end-of-line marker
+		// so the whole subsection is read at once
+		records := make([]byte, count*xrefRecordLength)
+		n, err := ra.ReadAt(records, pos)
+		if err != nil && err != io.EOF {
+			return nil, 0, fmt.Errorf("unable to read xref entries: %w", err)
+		}
+		records = records[:n]
+		recordsAt := pos
+
+		for i := 0; i < count; i++ {
+			number := start + int64(i)
+
+			var r []byte
+			if rel := pos - recordsAt; rel+xrefRecordLength <= int64(len(records)) {
+				r = records[rel : rel+xrefRecordLength]
+			} else {
+				// the entries drifted beyond the records read
+				r = make([]byte, xrefRecordLength)
+				n, _ := ra.ReadAt(r, pos)
+				r = r[:n]
+			}
+
+			if xrefEntry, ok := readXrefRecord(r); ok {
+				xrefEntry.Number = number
+				entries = append(entries, xrefEntry)
+				pos += xrefRecordLength
+				continue
+			}
+
+			if strict {
+				return nil, 0, fmt.Errorf("xref entry for object %d at %d is not a 20-byte entry", number, pos)
+			}
+
+			// realign on the end of the line
+			l, next, err := readLineAt(ra, pos)
+			if err != nil {
+				return nil, 0, err
+			}
+			for strings.TrimSpace(l) == "" {
+				// an empty line left by a two-character end-of-line marker
+				l, next, err = readLineAt(ra, next)
+				if err != nil {
+					return nil, 0, err
+				}
+			}
+			pos = next
+
+			fields := strings.Fields(l)
+			if len(fields) != 3 {
+				return nil, 0, fmt.Errorf("invalid xref entry for object %d: %q", number, l)
+			}
+			xrefEntry, err := readXrefEntry(fields)
+			if err != nil {
+				return nil, 0, err
+			}
+			xrefEntry.Number = number
+			entries = append(entries, xrefEntry)
+		}
+	}
+
+	return entries, pos, nil
+}
+
+const xrefRecordLength = 20
+
+// readXrefRecord parses a 20-byte entry "nnnnnnnnnn ggggg n" followed by " \r", " \n" or "\r\n".
+// It returns false if r does not conform.
+func readXrefRecord(r []byte) (XrefEntry, bool) {
+	if len(r) != xrefRecordLength {
+		return XrefEntry{}, false
+	}
+	if !isDigits(string(r[0:10])) || r[10] != ' ' || !isDigits(string(r[11:16])) || r[16] != ' ' {
+		return XrefEntry{}, false
+	}
+	if r[17] != 'n' && r[17] != 'f' {
+		return XrefEntry{}, false
+	}
+	switch string(r[18:20]) {
+	case " \r", " \n", "\r\n":
+	default:
+		return XrefEntry{}, false
+	}
+
+	offset, _ := strconv.ParseInt(string(r[0:10]), 10, 64)
+	generation, _ := strconv.Atoi(string(r[11:16]))
+	return XrefEntry{
+		ByteOffset: offset,
+		Generation: generation,
+		InUse:      r[17] == 'n',
+	}, true
+}
+
+// readLineAt reads a line at offset and returns it with the offset of the next line.
+// A line ends with CR, LF or CR LF.
+func readLineAt(ra io.ReaderAt, offset int64) (string, int64, error) {
+	var line []byte
+	buf := make([]byte, 256)
+	for pos := offset; ; {
+		n, err := ra.ReadAt(buf, pos)
+		if i := bytes.IndexAny(buf[:n], "\r\n"); i >= 0 {
+			line = append(line, buf[:i]...)
+			next := pos + int64(i) + 1
+			if buf[i] == '\r' {
+				// CR LF may span the end of buf
+				lf := make([]byte, 1)
+				if i+1 < n {
+					lf[0] = buf[i+1]
+				} else if _, err := ra.ReadAt(lf, next); err != nil {
+					lf[0] = 0
+				}
+				if lf[0] == '\n' {
+					next++
+				}
+			}
+			return string(line), next, nil
+		}
+		line = append(line, buf[:n]...)
+		pos += int64(n)
+
+		if err == io.EOF {
+			if len(line) == 0 {
+				return "", 0, io.ErrUnexpectedEOF
+			}
+			return string(line), pos, nil
+		}
+		if err != nil {
+			return "", 0, fmt.Errorf("unable to read a line: %w", err)
+		}
+		if len(line) > maxLineLength {
+			return "", 0, errors.New("line is too long")
+		}
+	}
+}
+
+func readXrefEntry(entry []string) (XrefEntry, error) {
+	var xrefEntry XrefEntry
+
+	offset, err := strconv.ParseInt(entry[0], 10, 64)
+	if err != nil {
+		return xrefEntry, fmt.Errorf("unable to read xref entry offset: %w", err)
+	}
+	xrefEntry.ByteOffset = offset
+
+	generation, err := strconv.Atoi(entry[1])
+	if err != nil {
+		return xrefEntry, fmt.Errorf("unable to read xref entry generation: %w", err)
+	}
+	xrefEntry.Generation = generation
+
+	if strings.HasPrefix(entry[2], "n") {
+		xrefEntry.InUse = true
+	}
+
+	return xrefEntry, nil
+}
+
+// DumpAt writes length bytes at offset in r to w in the format of hexdump -C with offsets in r.
+// Fewer bytes are written at the end of r.
+func DumpAt(w io.Writer, r io.ReaderAt, offset, length int64) error {
+	b, err := io.ReadAll(io.LimitReader(NewAtReader(r, offset), length))
+	if err != nil {
+		return fmt.Errorf("unable to read at %d: %w", offset, err)
+	}
+	return HexDump(w, b, offset)
+}
+
+// HexDump writes 16 bytes of b per line with the offset from baseOffset, hexadecimal and printable ASCII columns
+// as hexdump -C does. baseOffset is the offset of b in the file or 0.
+func HexDump(w io.Writer, b []byte, baseOffset int64) error {
+	bw := bufio.NewWriter(w)
+	for i := 0; i < len(b); i += 16 {
+		line := b[i:]
+		if len(line) > 16 {
+			line = line[:16]
+		}
+
+		fmt.Fprintf(bw, "%08x  ", baseOffset+int64(i))
+		for j := 0; j < 16; j++ {
+			if j < len(line) {
+				fmt.Fprintf(bw, "%02x ", line[j])
+			} else {
+				bw.WriteString("   ")
+			}
+			if j == 7 {
+				bw.WriteByte(' ')
+			}
+		}
+
+		bw.WriteString(" |")
+		for _, c := range line {
+			if c < 0x20 || c > 0x7e {
+				c = '.'
+			}
+			bw.WriteByte(c)
+		}
+		bw.WriteString("|\n")
+	}
+	fmt.Fprintf(bw, "%08x\n", baseOffset+int64(len(b)))
+	return bw.Flush()
+}
+
+// isObjectHeader reports whether l starts with "N G obj".
+func isObjectHeader(l string) bool {
+	fields := strings.Fields(l)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "obj") {
+		return false
+	}
+	for _, f := range fields[:2] {
+		if _, err := strconv.ParseInt(f, 10, 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// findTrailerInBlock returns the position of the trailer associated with the last startxref.
+func findTrailerInBlock(b []byte) int {
+	if sx := bytes.LastIndex(b, []byte("startxref")); sx >= 0 {
+		b = b[:sx]
+	}
+	return bytes.LastIndex(b, []byte("trailer"))
+}
+
+// isTrailerInBlock reports whether b has startxref followed by %%EOF and the trailer
+// dictionary preceding startxref if any.
+func isTrailerInBlock(b []byte) bool {
+	sx := bytes.LastIndex(b, []byte("startxref"))
+	if sx < 0 || !bytes.Contains(b[sx:], []byte("%%EOF")) {
+		return false
+	}
+
+	// files with cross-reference streams have no trailer dictionary
+	before := bytes.TrimRight(b[:sx], "\x00\t\n\f\r ")
+	if len(before) == 0 {
+		// the end of the preceding object is out of b
+		return false
+	}
+	if bytes.HasSuffix(before, []byte(">>")) {
+		return findTrailerInBlock(b) >= 0
+	}
+	return true
+}
+
+type AtReader struct {
+	ra     io.ReaderAt
+	offset int64
+
+	// size is negative when it is unknown
+	size int64
+}
+
+func NewAtReader(ra io.ReaderAt, offset int64) *AtReader {
+	return &AtReader{ra: ra, offset: offset, size: -1}
+}
+
+// NewSizedAtReader returns AtReader which also supports io.SeekEnd.
+func NewSizedAtReader(ra io.ReaderAt, offset, size int64) *AtReader {
+	return &AtReader{ra: ra, offset: offset, size: size}
+}
+
+func (ar *AtReader) Read(p []byte) (int, error) {
+	n, err := ar.ra.ReadAt(p, ar.offset)
+	if err != nil && err != io.EOF {
+		return n, err
+	}
+
+	ar.offset += int64(n)
+	return n, err
+}
+
+func (ar *AtReader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = ar.offset + offset
+	case io.SeekEnd:
+		if ar.size < 0 {
+			return 0, errors.New("unable to seek from the end without knowing the size")
+		}
+		abs = ar.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if abs < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	ar.offset = abs
+	return abs, nil
+}
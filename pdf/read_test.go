@@ -0,0 +1,327 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// benchmarkObjects is the number of objects in the file of the benchmarks for a large cross-reference table.
+const benchmarkObjects = 500000
+
+func BenchmarkListXrefEntries(b *testing.B) {
+	pdf := buildClassicPDF(benchmarkObjects)
+	tr, err := ReadTrailer(bytes.NewReader(pdf), int64(len(pdf)))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		entries, err := tr.ListXrefEntries()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(entries) != benchmarkObjects+1 {
+			b.Fatalf("got %d entries", len(entries))
+		}
+	}
+}
+
+// BenchmarkResolveAll opens the file and resolves every object without the cache of a previous iteration.
+func BenchmarkResolveAll(b *testing.B) {
+	pdf := buildClassicPDF(benchmarkObjects)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d, err := Open(bytes.NewReader(pdf), int64(len(pdf)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, ent := range d.XrefEntries() {
+			if !ent.InUse {
+				continue
+			}
+			if _, err := d.Resolve(PDFRef{Number: ent.Number, Generation: ent.Generation}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestReadTrailerPadded(t *testing.T) {
+	original := buildClassicPDF(3)
+	startxref := int64(bytes.LastIndex(original, []byte("xref\n0 4")))
+
+	// two trailers within the last 1024 bytes
+	var updated bytes.Buffer
+	if err := openBytes(t, original).AppendUpdate(&updated, map[int64]PDFObject{3: PDFDict{"Index": PDFInt(3)}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		pdf       []byte
+		startxref int64
+		prev      bool
+	}{
+		{
+			name:      "trailer dictionary longer than 1024 bytes",
+			pdf:       bytes.Replace(original, []byte("/Root 1 0 R >>"), []byte("/Root 1 0 R /Pad ("+strings.Repeat("x", 2000)+") >>"), 1),
+			startxref: startxref,
+		},
+		{
+			name:      "padding after %%EOF",
+			pdf:       append(append([]byte(nil), original...), bytes.Repeat([]byte("\x00"), 3000)...),
+			startxref: startxref,
+		},
+		{
+			name:      "padding before startxref",
+			pdf:       bytes.Replace(original, []byte(">>\nstartxref"), []byte(">>\n"+strings.Repeat(" \n", 1500)+"startxref"), 1),
+			startxref: startxref,
+		},
+		{
+			name:      "last of the trailers",
+			pdf:       updated.Bytes(),
+			startxref: int64(bytes.LastIndex(updated.Bytes(), []byte("xref\n3 1"))),
+			prev:      true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr, err := ReadTrailer(bytes.NewReader(tc.pdf), int64(len(tc.pdf)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tr.StartXref != tc.startxref {
+				t.Errorf("got startxref %d, want %d", tr.StartXref, tc.startxref)
+			}
+			if tr.Size != 4 {
+				t.Errorf("got /Size %d, want 4", tr.Size)
+			}
+			if _, ok := tr.Dict["Prev"]; ok != tc.prev {
+				t.Errorf("got %v, want /Prev %v", tr.Dict, tc.prev)
+			}
+			checkClassicObjects(t, openBytes(t, tc.pdf), 3)
+		})
+	}
+}
+
+func TestOpenBytesBeforeHeader(t *testing.T) {
+	junk := append([]byte("HTTP/1.1 200 OK\r\nContent-Type: application/pdf\r\n\r\n"), bytes.Repeat([]byte{0xde, 0xad}, 25)...)
+	junk = junk[:100]
+
+	for _, tc := range []struct {
+		name string
+		pdf  []byte
+	}{
+		{name: "xref table", pdf: buildClassicPDF(5)},
+		{name: "xref stream", pdf: buildXrefStreamPDF(map[int64]string{
+			1: "<< /Type /Catalog >>",
+			2: "<< /Index 2 >>",
+			3: "<< /Index 3 >>",
+			4: "<< /Index 4 >>",
+			5: "<< /Index 5 >>",
+		}, nil)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pdf := append(append([]byte(nil), junk...), tc.pdf...)
+			d := openBytes(t, pdf)
+			if got := d.HeaderOffset(); got != 100 {
+				t.Errorf("got the header offset %d, want 100", got)
+			}
+			checkClassicObjects(t, d, 5)
+
+			// an update keeps offsets relative to the header
+			var updated bytes.Buffer
+			if err := d.AppendUpdate(&updated, map[int64]PDFObject{5: PDFDict{"Index": PDFInt(5), "Updated": PDFBool(true)}}); err != nil {
+				t.Fatal(err)
+			}
+			d = openBytes(t, updated.Bytes())
+			if got := d.HeaderOffset(); got != 100 {
+				t.Errorf("got the header offset %d after the update, want 100", got)
+			}
+			checkClassicObjects(t, d, 5)
+			if dict, err := d.GetDict(5, 0); err != nil || dict["Updated"] != PDFBool(true) {
+				t.Errorf("got %v, %v", dict, err)
+			}
+		})
+	}
+
+	// offsets are not shifted without bytes before the header
+	if got := openBytes(t, buildClassicPDF(5)).HeaderOffset(); got != 0 {
+		t.Errorf("got the header offset %d, want 0", got)
+	}
+}
+
+func TestReadTrailerDict(t *testing.T) {
+	original := buildClassicPDF(3)
+	for _, tc := range []struct {
+		name, trailer string
+		size          int64
+	}{
+		{name: "one line", trailer: "trailer\n<< /Size 42 /Root 1 0 R >>", size: 42},
+		{name: "with the keyword", trailer: "trailer << /Size 42 /Root 1 0 R >>", size: 42},
+		{name: "no whitespace", trailer: "trailer<</Size 42/Root 1 0 R>>", size: 42},
+		{name: "nested /Size first", trailer: "trailer\n<< /Info << /Size 7 >> /Root 1 0 R /Size 42 >>", size: 42},
+		{name: "value on the next line", trailer: "trailer\n<<\n/Root 1 0 R\n/Size\n42\n>>", size: 42},
+		{name: "indirect", trailer: "trailer\n<< /Size 5 0 R /Root 1 0 R >>", size: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pdf := bytes.Replace(original, []byte("trailer\n<< /Size 4 /Root 1 0 R >>"), []byte(tc.trailer), 1)
+			tr, err := ReadTrailer(bytes.NewReader(pdf), int64(len(pdf)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tr.Size != tc.size {
+				t.Errorf("got /Size %d, want %d", tr.Size, tc.size)
+			}
+			if got := tr.Dict["Root"]; got != (PDFRef{Number: 1}) {
+				t.Errorf("got /Root %v", got)
+			}
+			checkClassicObjects(t, openBytes(t, pdf), 3)
+		})
+	}
+
+	// the fixture has the whole trailer on one line with /ID and /Info
+	b, err := os.ReadFile(filepath.Join("testdata", "trailer-one-line.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := ReadTrailer(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Size != 9 || tr.Dict["Info"] != (PDFRef{Number: 8}) || tr.Dict["Root"] != (PDFRef{Number: 1}) {
+		t.Errorf("got %d, %v", tr.Size, tr.Dict)
+	}
+	if id, _ := tr.Dict["ID"].(PDFArray); len(id) != 2 {
+		t.Errorf("got /ID %v", tr.Dict["ID"])
+	}
+}
+
+func TestHybridXRefStm(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "hybrid.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ra, size := bytes.NewReader(b), int64(len(b))
+
+	tr, err := ReadTrailer(ra, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tr.Dict["XRefStm"].(PDFInt); !ok {
+		t.Fatalf("got %v, want /XRefStm", tr.Dict)
+	}
+	// the objects in the object stream are free in the table for readers without cross-reference streams
+	section, err := tr.ListXrefEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ent := range section {
+		if ent.Number == 1 && ent.InUse {
+			t.Fatalf("got %+v, want 1 0 R free in the table", ent)
+		}
+	}
+
+	// 7.5.8.4 the entries of /XRefStm take precedence over the table
+	entries, err := tr.ResolveAllEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed := map[int64]bool{}
+	for _, ent := range entries {
+		if ent.InUse && ent.Compressed {
+			compressed[ent.Number] = true
+		}
+	}
+	for _, n := range []int64{1, 2, 3, 5, 6} {
+		if !compressed[n] {
+			t.Errorf("%d 0 R is not in the object stream", n)
+		}
+	}
+
+	d, err := Open(ra, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog, err := d.Catalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if catalog["Type"] != PDFName("Catalog") {
+		t.Errorf("got %v", catalog)
+	}
+	pages, err := d.Pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 2 {
+		t.Errorf("got %d pages, want 2", len(pages))
+	}
+}
+
+func TestReadLongLine(t *testing.T) {
+	// a single-line dictionary and array longer than the 64KB limit of bufio.Scanner
+	var dict, arr strings.Builder
+	dict.WriteString("<<")
+	arr.WriteString("[")
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&dict, " /Key%d (value %d)", i, i)
+		fmt.Fprintf(&arr, " %d", i)
+	}
+	dict.WriteString(" >>")
+	arr.WriteString("]")
+	if dict.Len() <= 64*1024 || arr.Len() <= 64*1024/2 {
+		t.Fatalf("the lines are too short: %d and %d bytes", dict.Len(), arr.Len())
+	}
+
+	pdf := buildClassicPDFObjects(map[int64]string{
+		1: "<< /Type /Catalog >>",
+		2: dict.String(),
+		// the header and endobj are on the same line
+		3: "3 0 obj " + dict.String() + " endobj",
+		4: "<< /Array " + arr.String() + strings.Repeat(" ", 64*1024) + ">>",
+		5: "(after the long lines)",
+	}, " /Pad ("+strings.Repeat("x", 100*1024)+")")
+
+	tr, err := ReadTrailer(bytes.NewReader(pdf), int64(len(pdf)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := tr.ListXrefEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 6 {
+		t.Fatalf("got %d entries, want 6", len(entries))
+	}
+
+	d := openBytes(t, pdf)
+	for _, number := range []int64{2, 3} {
+		obj, err := d.GetObject(number, 0)
+		if err != nil {
+			t.Fatalf("%d 0 R: %v", number, err)
+		}
+		got, _ := obj.(PDFDict)
+		if s, _ := got["Key9999"].(PDFString); len(got) != 10000 || string(s) != "value 9999" {
+			t.Errorf("%d 0 R: got %d entries", number, len(got))
+		}
+	}
+	obj, err := d.Resolve(PDFRef{Number: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := obj.(PDFDict)["Array"].(PDFArray); len(got) != 10000 || got[9999] != PDFInt(9999) {
+		t.Errorf("got %d elements", len(got))
+	}
+	if obj, err := d.GetObject(5, 0); err != nil || !reflect.DeepEqual(obj, PDFString("after the long lines")) {
+		t.Errorf("got %v, %v", obj, err)
+	}
+}
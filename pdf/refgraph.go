@@ -0,0 +1,67 @@
+package pdf
+
+import "errors"
+
+// RefNode is an indirect object in the reference graph returned by RefGraph.
+type RefNode struct {
+	// Path is where the reference is in the referring object such as /Kids[0]
+	Path string
+	Ref  PDFRef
+	// Type is /Type of the dictionary or the stream dictionary if any
+	Type PDFName
+
+	// Seen is set when the object already appears earlier in the tree. Its references are not followed.
+	Seen bool
+	// Truncated is set when the references are not followed because of the depth limit
+	Truncated bool
+	// Err is set when the object cannot be read
+	Err error
+
+	Children []*RefNode
+}
+
+// RefGraph returns the tree of indirect objects reachable from /Root in the depth-first order.
+// Each object is expanded once and its later appearances are marked as Seen, so cycles end there.
+// References deeper than maxDepth or MaxDepth are not followed. maxDepth <= 0 means no limit other than MaxDepth.
+func (d *Document) RefGraph(maxDepth int) (*RefNode, error) {
+	root, ok := d.trailerDict["Root"].(PDFRef)
+	if !ok {
+		return nil, errors.New("trailer must have /Root as an indirect reference")
+	}
+
+	seen := map[PDFRef]bool{}
+	var visit func(node *RefNode, depth int)
+	visit = func(node *RefNode, depth int) {
+		seen[node.Ref] = true
+
+		obj, err := d.readObject(node.Ref)
+		if err != nil {
+			node.Err = err
+			return
+		}
+		switch obj := obj.(type) {
+		case PDFDict:
+			node.Type, _ = obj["Type"].(PDFName)
+		case PDFStream:
+			node.Type, _ = obj.Dict["Type"].(PDFName)
+		}
+
+		walkRefs(obj, "", func(path string, ref PDFRef) {
+			node.Children = append(node.Children, &RefNode{Path: path, Ref: ref})
+		})
+		for _, child := range node.Children {
+			switch {
+			case seen[child.Ref]:
+				child.Seen = true
+			case maxDepth > 0 && depth >= maxDepth, d.checkDepth(depth+1, child.Ref) != nil:
+				child.Truncated = true
+			default:
+				visit(child, depth+1)
+			}
+		}
+	}
+
+	node := &RefNode{Path: "/Root", Ref: root}
+	visit(node, 1)
+	return node, nil
+}
@@ -0,0 +1,129 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// String methods render the object model back into PDF syntax so that
+// ParseObject(obj.String()) returns obj. Keys of dictionaries are sorted.
+
+func (d PDFDict) String() string {
+	keys := make([]string, 0, len(d))
+	for k := range d {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	b.WriteString("<<")
+	for _, k := range keys {
+		b.WriteByte(' ')
+		b.WriteString(PDFName(k).String())
+		b.WriteByte(' ')
+		b.WriteString(renderObject(d[k]))
+	}
+	b.WriteString(" >>")
+	return b.String()
+}
+
+func (a PDFArray) String() string {
+	var b bytes.Buffer
+	b.WriteByte('[')
+	for i := range a {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(renderObject(a[i]))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// 7.3.5 Name Objects
+// Any character except regular characters in the printable range is written as #xx.
+func (n PDFName) String() string {
+	var b bytes.Buffer
+	b.WriteByte('/')
+	for i := 0; i < len(n); i++ {
+		c := n[i]
+		if c < '!' || c > '~' || c == '#' || !isRegular(c) {
+			fmt.Fprintf(&b, "#%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// 7.3.4.2 Literal Strings and 7.3.4.3 Hexadecimal Strings
+// Strings of printable characters are written as literal strings with escapes
+// and anything else as hexadecimal strings.
+func (s PDFString) String() string {
+	var b bytes.Buffer
+	b.WriteByte('(')
+	for _, c := range s {
+		switch c {
+		case '\\', '(', ')':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			if c < ' ' || c > '~' {
+				return fmt.Sprintf("<%X>", []byte(s))
+			}
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+func (i PDFInt) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
+// Reals are always written with a decimal point and never with an exponent.
+func (r PDFReal) String() string {
+	s := strconv.FormatFloat(float64(r), 'f', -1, 64)
+	if !bytes.ContainsRune([]byte(s), '.') {
+		s += ".0"
+	}
+	return s
+}
+
+func (b PDFBool) String() string {
+	return strconv.FormatBool(bool(b))
+}
+
+func (PDFNull) String() string {
+	return "null"
+}
+
+func (r PDFRef) String() string {
+	return fmt.Sprintf("%d %d R", r.Number, r.Generation)
+}
+
+// The body is not a part of PDFStream so the stream is rendered as empty.
+func (s PDFStream) String() string {
+	return s.Dict.String() + "\nstream\nendstream"
+}
+
+func renderObject(obj PDFObject) string {
+	if s, ok := obj.(fmt.Stringer); ok {
+		return s.String()
+	}
+	// nil is not a valid object but is rendered as null
+	return "null"
+}
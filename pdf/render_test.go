@@ -0,0 +1,83 @@
+package pdf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderRoundTrip(t *testing.T) {
+	for _, obj := range []PDFObject{
+		PDFInt(0),
+		PDFInt(-42),
+		PDFReal(3.25),
+		PDFReal(-0.002),
+		PDFReal(12),
+		PDFReal(1e-7),
+		PDFBool(true),
+		PDFBool(false),
+		PDFNull{},
+		PDFRef{Number: 12, Generation: 3},
+		PDFName("Type"),
+		PDFName("A B"),
+		PDFName("paired()parentheses"),
+		PDFName("The_Key_of_F#_Minor"),
+		PDFName("a/b%c"),
+		PDFName("caf\xe9"),
+		PDFString("Hello"),
+		PDFString("These (are) balanced"),
+		PDFString("unbalanced ) ( and \\ backslash"),
+		PDFString("line\nfeed\r\ttab\b\f"),
+		PDFString("\xfe\xff\x00H\x00i"),
+		PDFString("\x00\x01\x02"),
+		PDFArray{},
+		PDFArray{PDFInt(1), PDFReal(0.5), PDFName("Name"), PDFString("s"), PDFRef{Number: 4}, PDFNull{}},
+		PDFArray{PDFArray{PDFArray{}}, PDFDict{}},
+		PDFDict{},
+		PDFDict{
+			"Type":     PDFName("Page"),
+			"Parent":   PDFRef{Number: 2},
+			"MediaBox": PDFArray{PDFInt(0), PDFInt(0), PDFReal(612.5), PDFInt(792)},
+			"Resources": PDFDict{
+				"Font": PDFDict{"F1": PDFRef{Number: 5}},
+			},
+			"Contents":     PDFArray{PDFRef{Number: 6}, PDFRef{Number: 7, Generation: 1}},
+			"A B":          PDFString("(x)"),
+			"Rotate":       PDFInt(-90),
+			"UserUnit":     PDFReal(1.5),
+			"Hidden":       PDFBool(true),
+			"Nothing":      PDFNull{},
+			"ID":           PDFArray{PDFString("\x8a\x01\xff"), PDFString("\x8a\x01\xff")},
+			"Key#WithHash": PDFName("Value/WithSlash"),
+		},
+	} {
+		rendered := renderObject(obj)
+		got, err := ParseObject([]byte(rendered))
+		if err != nil {
+			t.Errorf("%s: %v", rendered, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, obj) {
+			t.Errorf("%s: got %#v, want %#v", rendered, got, obj)
+		}
+	}
+}
+
+func TestRenderCanonical(t *testing.T) {
+	for _, tc := range []struct {
+		obj  PDFObject
+		want string
+	}{
+		{obj: PDFDict{"Type": PDFName("Catalog"), "Pages": PDFRef{Number: 2}}, want: "<< /Pages 2 0 R /Type /Catalog >>"},
+		{obj: PDFArray{PDFInt(1), PDFReal(2), PDFBool(false), PDFNull{}}, want: "[1 2.0 false null]"},
+		{obj: PDFReal(1e-7), want: "0.0000001"},
+		{obj: PDFName("A B"), want: "/A#20B"},
+		{obj: PDFString("(a)\\"), want: `(\(a\)\\)`},
+		{obj: PDFString("\xff\x00"), want: "<FF00>"},
+		{obj: PDFStream{Dict: PDFDict{"Length": PDFInt(0)}}, want: "<< /Length 0 >>\nstream\nendstream"},
+		{obj: nil, want: "null"},
+	} {
+		if got := renderObject(tc.obj); got != tc.want {
+			t.Errorf("%#v: got %q, want %q", tc.obj, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// NewRepairedDocument returns Document whose cross-reference entries are rebuilt
+// by scanning the whole file. It is useful when the cross-reference table or the trailer is broken.
+func NewRepairedDocument(ra io.ReaderAt, size int64) (*Document, error) {
+	d := &Document{
+		ra:          ra,
+		trailer:     Trailer{ra: ra, size: size},
+		cache:       map[PDFRef]PDFObject{},
+		objStmCache: map[int64]*objectStream{},
+	}
+
+	if _, err := d.RebuildXref(); err != nil {
+		return nil, err
+	}
+
+	// the empty user password is tried with /Encrypt and /ID recovered by the scan as NewDocument does
+	d.Decrypt("")
+
+	return d, nil
+}
+
+// objectHeaderPattern matches an object header or the trailer keyword
+var objectHeaderPattern = regexp.MustCompile(`(\d+)[\x00\t\f\r\n ]+(\d+)[\x00\t\f\r\n ]+obj|trailer`)
+
+// recoveredTrailerKeys are the entries of trailers found by the scan which are kept in the rebuilt trailer.
+// /Root is the catalog found by the scan and /Size is computed from the entries.
+var recoveredTrailerKeys = []string{"Encrypt", "Info", "ID"}
+
+// scannedTrailer is a trailer dictionary or the dictionary of a cross-reference stream found by the scan.
+type scannedTrailer struct {
+	offset int64
+	dict   PDFDict
+}
+
+// RebuildXref scans the whole file for object headers and replaces the cross-reference entries
+// with them. When an object number appears more than once, the latest generation wins
+// and the last one wins within the same generation.
+// Objects in object streams found by the scan are also added.
+// The trailer has /Size, /Root of the catalog found by the scan and /Encrypt, /Info and /ID
+// of the last trailer or cross-reference stream having them.
+func (d *Document) RebuildXref() ([]XrefEntry, error) {
+	return d.RebuildXrefContext(context.Background())
+}
+
+// RebuildXrefContext is the same as RebuildXref but aborts scanning the file when ctx is done.
+// The entries may be partially replaced when it is aborted.
+func (d *Document) RebuildXrefContext(ctx context.Context) ([]XrefEntry, error) {
+	const chunkSize = 1 << 20
+	// keep some bytes after the chunk so that a header across chunks can be matched
+	const overlap = 64
+
+	size := d.trailer.size
+	found := map[int64]XrefEntry{}
+	var trailers []scannedTrailer
+
+	var skipUntil int64
+	buf := make([]byte, chunkSize+overlap)
+	for pos := int64(0); pos < size; pos += chunkSize {
+		if err := contextErr(ctx); err != nil {
+			return nil, err
+		}
+
+		n, err := d.ra.ReadAt(buf, pos)
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("unable to scan the file: %w", err)
+		}
+		chunk := buf[:n]
+
+		for _, m := range objectHeaderPattern.FindAllSubmatchIndex(chunk, -1) {
+			if m[0] >= chunkSize {
+				// the next chunk will see it
+				continue
+			}
+
+			start := pos + int64(m[0])
+			if start < skipUntil {
+				continue
+			}
+
+			// the header must be a separate token
+			if m[0] > 0 && isRegular(chunk[m[0]-1]) {
+				continue
+			}
+			if m[1] < len(chunk) && isRegular(chunk[m[1]]) {
+				continue
+			}
+
+			if m[2] < 0 {
+				// the trailer keyword
+				if dict, err := readTrailerDict(d.ra, start); err == nil {
+					trailers = append(trailers, scannedTrailer{offset: start, dict: dict})
+				}
+				continue
+			}
+
+			number, err := strconv.ParseInt(string(chunk[m[2]:m[3]]), 10, 64)
+			if err != nil {
+				continue
+			}
+			generation, err := strconv.Atoi(string(chunk[m[4]:m[5]]))
+			if err != nil {
+				continue
+			}
+
+			if prev, ok := found[number]; !ok || prev.Generation <= generation {
+				found[number] = XrefEntry{
+					ByteOffset: start,
+					Number:     number,
+					Generation: generation,
+					InUse:      true,
+				}
+			}
+
+			// stream bodies may contain anything that looks like an object header
+			if end, ok := streamEnd(d.ra, start); ok {
+				skipUntil = end
+			}
+		}
+	}
+
+	if len(found) == 0 {
+		return nil, errors.New("no object found")
+	}
+
+	entries := make([]XrefEntry, 0, len(found))
+	for _, ent := range found {
+		entries = append(entries, ent)
+	}
+	sortXrefEntries(entries)
+
+	d.entries = entries
+	d.entryIndex = nil
+	d.cache = map[PDFRef]PDFObject{}
+	d.objStmCache = map[int64]*objectStream{}
+
+	var catalog *PDFRef
+	for _, ent := range entries {
+		if err := contextErr(ctx); err != nil {
+			return nil, err
+		}
+
+		ref := PDFRef{Number: ent.Number, Generation: ent.Generation}
+		obj, err := d.ResolveContext(ctx, ref)
+		if err != nil {
+			continue
+		}
+
+		switch obj := obj.(type) {
+		case PDFDict:
+			if typ, _ := obj["Type"].(PDFName); typ == "Catalog" {
+				catalog = &ref
+			}
+		case PDFStream:
+			typ, _ := obj.Dict["Type"].(PDFName)
+			if typ == "XRef" {
+				// 7.5.8.2 the dictionary of a cross-reference stream is the trailer
+				trailers = append(trailers, scannedTrailer{offset: ent.ByteOffset, dict: obj.Dict})
+				continue
+			}
+			if typ != "ObjStm" {
+				continue
+			}
+			stm, err := d.objectStream(ent.Number)
+			if err != nil {
+				continue
+			}
+			for i, number := range stm.numbers {
+				if _, ok := found[number]; ok {
+					continue
+				}
+				found[number] = XrefEntry{
+					Number:       number,
+					InUse:        true,
+					Compressed:   true,
+					StreamNumber: ent.Number,
+					StreamIndex:  i,
+				}
+				d.entries = append(d.entries, found[number])
+				d.entryIndex = nil
+			}
+		}
+	}
+	sortXrefEntries(d.entries)
+
+	if catalog == nil {
+		// the catalog may live in an object stream
+		for _, ent := range d.entries {
+			if !ent.Compressed {
+				continue
+			}
+			ref := PDFRef{Number: ent.Number, Generation: ent.Generation}
+			if obj, err := d.Resolve(ref); err == nil {
+				if dict, ok := obj.(PDFDict); ok {
+					if typ, _ := dict["Type"].(PDFName); typ == "Catalog" {
+						catalog = &ref
+						break
+					}
+				}
+			}
+		}
+	}
+
+	// 7.5.5 /Size is one greater than the highest object number
+	maxNumber := int64(0)
+	for _, ent := range d.entries {
+		if ent.Number > maxNumber {
+			maxNumber = ent.Number
+		}
+	}
+	d.trailerDict = PDFDict{"Size": PDFInt(maxNumber + 1)}
+	if catalog != nil {
+		d.trailerDict["Root"] = *catalog
+	}
+
+	// the last one in the file is the newest
+	sort.SliceStable(trailers, func(i, j int) bool { return trailers[i].offset < trailers[j].offset })
+	for _, key := range recoveredTrailerKeys {
+		for i := len(trailers) - 1; i >= 0; i-- {
+			v, ok := trailers[i].dict[key]
+			if !ok {
+				continue
+			}
+			// a reference to an object which is not found is dropped
+			if ref, ok := v.(PDFRef); ok {
+				if ent, err := d.XrefEntry(ref.Number, ref.Generation); err != nil || !ent.InUse {
+					continue
+				}
+			}
+			d.trailerDict[key] = v
+			break
+		}
+	}
+
+	return d.entries, nil
+}
+
+// streamEnd returns the offset after the body of the stream object at offset.
+func streamEnd(ra io.ReaderAt, offset int64) (int64, bool) {
+	dict, bodyOffset, err := readStreamHeader(ra, offset)
+	if err != nil {
+		return 0, false
+	}
+
+	if length, ok := dict["Length"].(PDFInt); ok && length >= 0 {
+		end := bodyOffset + int64(length)
+		if isFollowedByEndstream(ra, end) {
+			return end, true
+		}
+	}
+
+	end, err := findEndstream(ra, bodyOffset)
+	if err != nil {
+		return 0, false
+	}
+	return end, true
+}
+
+func sortXrefEntries(entries []XrefEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Number != entries[j].Number {
+			return entries[i].Number < entries[j].Number
+		}
+		return entries[i].Generation < entries[j].Generation
+	})
+}
@@ -0,0 +1,129 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// openRepaired rebuilds the cross-reference entries of the PDF in b.
+func openRepaired(t *testing.T, b []byte) *Document {
+	t.Helper()
+	d, err := NewRepairedDocument(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestRebuildXrefTrailer(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "rc4-blank-password.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Open(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// break the cross-reference table
+	broken := bytes.Replace(b, []byte("xref\n0 "), []byte("xerf\n0 "), 1)
+	d := openRepaired(t, broken)
+
+	tr := d.TrailerDict()
+	for _, key := range []string{"Root", "Encrypt", "Info", "ID", "Size"} {
+		if !reflect.DeepEqual(tr[key], want.TrailerDict()[key]) {
+			t.Errorf("/%s: got %v, want %v", key, tr[key], want.TrailerDict()[key])
+		}
+	}
+
+	// the document is decrypted with the empty user password by the recovered /Encrypt and /ID
+	info, err := d.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Title != "Encrypted title" {
+		t.Errorf("got the title %q", info.Title)
+	}
+	pages, err := d.Pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) == 0 {
+		t.Fatal("no page")
+	}
+	text, err := d.PageText(pages[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "Secret text") {
+		t.Errorf("got the text %q", text)
+	}
+}
+
+func TestRebuildXrefStreamTrailer(t *testing.T) {
+	// the cross-reference stream is 11 0 obj and the numbers have a gap
+	b := buildXrefStreamPDF(map[int64]string{
+		1:  "<< /Type /Catalog >>",
+		2:  "<< /Title (From the xref stream) >>",
+		10: "<< /Index 10 >>",
+	}, nil)
+	b = bytes.Replace(b, []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Info 2 0 R /ID [<01> <02>]"), 1)
+	d := openRepaired(t, b)
+
+	tr := d.TrailerDict()
+	if got := tr["Size"]; got != PDFInt(12) {
+		t.Errorf("got /Size %v, want 12", got)
+	}
+	if got := len(d.XrefEntries()); got != 4 {
+		t.Errorf("got %d entries, want 4", got)
+	}
+	if got := tr["Info"]; got != (PDFRef{Number: 2}) {
+		t.Errorf("got /Info %v", got)
+	}
+	if got := tr["ID"]; !reflect.DeepEqual(got, PDFArray{PDFString("\x01"), PDFString("\x02")}) {
+		t.Errorf("got /ID %v", got)
+	}
+	if _, ok := tr["Encrypt"]; ok {
+		t.Errorf("got /Encrypt %v", tr["Encrypt"])
+	}
+}
+
+func TestRebuildXrefNewestTrailer(t *testing.T) {
+	original := bytes.Replace(buildClassicPDF(3), []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Info 2 0 R /ID [<01> <01>]"), 1)
+
+	for _, tc := range []struct {
+		name    string
+		trailer string
+		info    PDFRef
+	}{
+		{name: "newer /Info", trailer: "<< /Size 5 /Root 1 0 R /Info 4 0 R >>", info: PDFRef{Number: 4}},
+		{name: "missing object", trailer: "<< /Size 5 /Root 1 0 R /Info 9 0 R >>", info: PDFRef{Number: 2}},
+		{name: "no /Info", trailer: "<< /Size 5 /Root 1 0 R >>", info: PDFRef{Number: 2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := append(append([]byte(nil), original...), fmt.Sprintf(
+				"4 0 obj\n<< /Title (Updated) >>\nendobj\nxref\n0 1\n0000000000 65535 f \ntrailer\n%s\nstartxref\n0\n%%%%EOF\n", tc.trailer)...)
+			d := openRepaired(t, b)
+
+			tr := d.TrailerDict()
+			if got := tr["Info"]; got != tc.info {
+				t.Errorf("got /Info %v, want %v", got, tc.info)
+			}
+			// /ID is only in the older trailer
+			if got := tr["ID"]; !reflect.DeepEqual(got, PDFArray{PDFString("\x01"), PDFString("\x01")}) {
+				t.Errorf("got /ID %v", got)
+			}
+			if got := tr["Size"]; got != PDFInt(5) {
+				t.Errorf("got /Size %v, want 5", got)
+			}
+			if got := tr["Root"]; got != (PDFRef{Number: 1}) {
+				t.Errorf("got /Root %v", got)
+			}
+		})
+	}
+}
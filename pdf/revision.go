@@ -0,0 +1,84 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Revisions returns the revisions of the document from the oldest by following /Prev.
+// The first one is the original document and each of the others is an incremental update.
+func (d *Document) Revisions() ([]Revision, error) {
+	return d.trailer.revisions(context.Background())
+}
+
+// RevisionTimestamps returns /ModDate in /Info of the trailer of each revision in the order of Revisions.
+// /Info is read as of the revision so that a dictionary replaced by a later update gives the prior date.
+// The time is zero when the revision has no /Info or /ModDate, or the date is malformed.
+func (d *Document) RevisionTimestamps() ([]time.Time, error) {
+	revisions, err := d.Revisions()
+	if err != nil {
+		return nil, err
+	}
+
+	timestamps := make([]time.Time, len(revisions))
+	entries := map[int64]XrefEntry{}
+	for i, rev := range revisions {
+		for _, ent := range rev.Entries {
+			entries[ent.Number] = ent
+		}
+
+		var info PDFObject
+		switch obj := rev.Trailer["Info"].(type) {
+		case PDFDict:
+			info = obj
+		case PDFRef:
+			ent, ok := entries[obj.Number]
+			if !ok || !ent.InUse || ent.Generation != obj.Generation {
+				continue
+			}
+			info, err = d.loadObject(obj, ent)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read /Info of revision %d: %w", i, err)
+			}
+		}
+		dict, ok := info.(PDFDict)
+		if !ok {
+			continue
+		}
+
+		// a date in an indirect object is resolved in the latest revision
+		obj, err := d.Resolve(dict["ModDate"])
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve /ModDate of revision %d: %w", i, err)
+		}
+		s, _ := obj.(PDFString)
+		timestamps[i], _ = ParseDate(DecodeTextString(s))
+	}
+	return timestamps, nil
+}
+
+// ObjectRevisions returns the entries of the object number in every revision from the oldest.
+// Unlike XrefEntry, an entry overridden by a later revision is also returned so that
+// a prior version of the object can be read with ReadEntry.
+func (d *Document) ObjectRevisions(number int64) ([]XrefEntry, error) {
+	revisions, err := d.Revisions()
+	if err != nil {
+		return nil, err
+	}
+
+	var history []XrefEntry
+	for _, rev := range revisions {
+		// the last one wins within a revision as in resolving entries
+		var found *XrefEntry
+		for i := range rev.Entries {
+			if rev.Entries[i].Number == number {
+				found = &rev.Entries[i]
+			}
+		}
+		if found != nil {
+			history = append(history, *found)
+		}
+	}
+	return history, nil
+}
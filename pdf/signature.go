@@ -0,0 +1,105 @@
+package pdf
+
+import (
+	"fmt"
+	"time"
+)
+
+// SignatureInfo is the signature dictionary of a signed signature field. The signature is not verified.
+// 12.8.1 Table 252 Entries in a signature dictionary
+type SignatureInfo struct {
+	// Field is the fully qualified name of the signature field
+	Field string
+	// Ref is the signature dictionary or the zero PDFRef when it is a direct object
+	Ref PDFRef
+
+	Filter    PDFName
+	SubFilter PDFName
+	// ByteRange has pairs of the offset and the length of the signed bytes
+	ByteRange []int64
+
+	// Name is /Name which is empty when the signer is only in the certificate
+	Name     string
+	Reason   string
+	Location string
+	// Time is /M or zero. It is claimed by the signer and is not a trusted timestamp.
+	Time time.Time
+
+	// SignedEnd is the end of the last signed range, which is the end of the signed revision
+	SignedEnd int64
+	// CoversFile is true when the signed ranges reach the end of the file
+	CoversFile bool
+	// UpdatesAfter is the number of incremental updates whose cross-reference section is after SignedEnd.
+	// They are not covered by the signature and may have modified the document after signing.
+	UpdatesAfter int
+}
+
+// Signatures returns the signatures of the signature fields in the order of FormFields.
+// A signature field without /V is not signed and is not included.
+func (d *Document) Signatures() ([]SignatureInfo, error) {
+	fields, err := d.FormFields()
+	if err != nil {
+		return nil, err
+	}
+	var revisions []Revision
+
+	var sigs []SignatureInfo
+	for _, field := range fields {
+		dict, ok := field.Value.(PDFDict)
+		if field.Type != "Sig" || !ok {
+			continue
+		}
+		sig := SignatureInfo{Field: field.Name}
+		sig.Ref, _ = field.Dict["V"].(PDFRef)
+		sig.Filter, _ = dict["Filter"].(PDFName)
+		sig.SubFilter, _ = dict["SubFilter"].(PDFName)
+
+		text := func(key string) string {
+			obj, err := d.Resolve(dict[key])
+			if err != nil {
+				return ""
+			}
+			s, _ := obj.(PDFString)
+			return DecodeTextString(s)
+		}
+		sig.Name = text("Name")
+		sig.Reason = text("Reason")
+		sig.Location = text("Location")
+		sig.Time, _ = ParseDate(text("M"))
+
+		obj, err := d.Resolve(dict["ByteRange"])
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve /ByteRange of field %q: %w", field.Name, err)
+		}
+		byteRange, _ := obj.(PDFArray)
+		if len(byteRange)%2 != 0 {
+			return nil, fmt.Errorf("/ByteRange of field %q must have pairs of an offset and a length", field.Name)
+		}
+		for i, elem := range byteRange {
+			n, ok := elem.(PDFInt)
+			if !ok || n < 0 {
+				return nil, fmt.Errorf("/ByteRange of field %q must have non-negative integers but got %v", field.Name, elem)
+			}
+			sig.ByteRange = append(sig.ByteRange, int64(n))
+			if i%2 == 1 && sig.ByteRange[i-1]+int64(n) > sig.SignedEnd {
+				sig.SignedEnd = sig.ByteRange[i-1] + int64(n)
+			}
+		}
+		sig.CoversFile = sig.SignedEnd == d.trailer.size
+
+		// 7.5.6 an incremental update appends its cross-reference section after the signed bytes
+		if revisions == nil {
+			revisions, err = d.Revisions()
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, rev := range revisions {
+			if rev.XrefOffset >= sig.SignedEnd {
+				sig.UpdatesAfter++
+			}
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
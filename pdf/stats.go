@@ -0,0 +1,91 @@
+package pdf
+
+// Stats summarizes objects in the document.
+type Stats struct {
+	// Objects is the number of in-use objects and Free is the number of free entries including object 0
+	Objects int
+	Free    int
+	// MaxObjectNumber is the highest object number in the cross-reference entries
+	MaxObjectNumber int64
+	ObjectStreams   int
+	// Types counts objects by "Type" or "Type/Subtype" of dictionaries and streams.
+	// /Type is replaced by the kind such as "dict", "stream" and "array" if it is missing.
+	Types map[string]int
+
+	Streams int
+	// StreamBytes is the total length of raw stream bodies and DecodedStreamBytes is the one after decoding.
+	// A stream which cannot be decoded is not included in DecodedStreamBytes.
+	StreamBytes        int64
+	DecodedStreamBytes int64
+
+	// Errors is the number of objects which cannot be read
+	Errors int
+}
+
+// Stats resolves every in-use object and counts them.
+func (d *Document) Stats() (Stats, error) {
+	stats := Stats{Types: map[string]int{}}
+
+	for _, ent := range d.entries {
+		if ent.Number > stats.MaxObjectNumber {
+			stats.MaxObjectNumber = ent.Number
+		}
+		if !ent.InUse {
+			stats.Free++
+			continue
+		}
+		stats.Objects++
+
+		ref := PDFRef{Number: ent.Number, Generation: ent.Generation}
+		obj, err := d.readObject(ref)
+		if err != nil {
+			stats.Errors++
+			continue
+		}
+
+		var dict PDFDict
+		kind := ""
+		switch obj := obj.(type) {
+		case PDFDict:
+			dict, kind = obj, "dict"
+		case PDFStream:
+			dict, kind = obj.Dict, "stream"
+			stats.Streams++
+
+			if _, length, err := d.streamBody(ent, obj.Dict); err == nil {
+				stats.StreamBytes += length
+			}
+			if _, b, err := d.readStream(ref); err == nil {
+				stats.DecodedStreamBytes += int64(len(b))
+			}
+		case PDFArray:
+			kind = "array"
+		case PDFString:
+			kind = "string"
+		case PDFName:
+			kind = "name"
+		case PDFInt, PDFReal:
+			kind = "number"
+		case PDFBool:
+			kind = "boolean"
+		case PDFNull:
+			kind = "null"
+		default:
+			kind = "other"
+		}
+
+		// /Type is optional for some objects such as image XObjects
+		if typ, ok := dict["Type"].(PDFName); ok {
+			kind = string(typ)
+			if typ == "ObjStm" {
+				stats.ObjectStreams++
+			}
+		}
+		if subtype, ok := dict["Subtype"].(PDFName); ok {
+			kind += "/" + string(subtype)
+		}
+		stats.Types[kind]++
+	}
+
+	return stats, nil
+}
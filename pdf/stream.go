@@ -0,0 +1,242 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ReadStreamBody reads the raw (undecoded) body of the stream object at ent.
+// dict is the stream dictionary. /Length is resolved if it is an indirect object.
+// The body is delimited by endstream when /Length is missing or wrong.
+// The body is decrypted if the document is decrypted.
+// ErrEncrypted is returned when the document is encrypted but not decrypted yet.
+func (d *Document) ReadStreamBody(ent XrefEntry, dict PDFDict) ([]byte, error) {
+	offset, length, err := d.streamBody(ent, dict)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, length)
+	if _, err := d.ra.ReadAt(raw, offset); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("unable to read stream: %w", err)
+	}
+
+	if d.crypt != nil && isEncryptedStream(dict, d.crypt.sec) {
+		ref := PDFRef{Number: ent.Number, Generation: ent.Generation}
+		raw, err = d.crypt.decryptBytes(d.crypt.streamMethod(dict), ref, raw)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt stream: %w", err)
+		}
+	}
+
+	return raw, nil
+}
+
+// streamBody returns the offset and the length of the raw body of the stream object at ent.
+func (d *Document) streamBody(ent XrefEntry, dict PDFDict) (int64, int64, error) {
+	encrypted, _, err := d.IsEncrypted()
+	if err != nil {
+		return 0, 0, err
+	}
+	if encrypted && d.crypt == nil {
+		return 0, 0, ErrEncrypted
+	}
+
+	_, bodyOffset, err := readStreamHeader(d.ra, ent.ByteOffset)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	length := int64(-1)
+	if obj, ok := dict["Length"]; ok {
+		resolved, err := d.Resolve(obj)
+		if err != nil {
+			return 0, 0, fmt.Errorf("unable to resolve /Length: %w", err)
+		}
+		if n, ok := resolved.(PDFInt); ok {
+			length = int64(n)
+		}
+	}
+
+	length, err = streamDataLength(d.ra, bodyOffset, length)
+	if err != nil {
+		return 0, 0, err
+	}
+	return bodyOffset, length, nil
+}
+
+// readStream returns the dictionary and the decoded body of the stream object ref.
+func (d *Document) readStream(ref PDFRef) (PDFDict, []byte, error) {
+	obj, err := d.Resolve(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	stream, ok := obj.(PDFStream)
+	if !ok {
+		return nil, nil, fmt.Errorf("%d %d R must be a stream but got %T", ref.Number, ref.Generation, obj)
+	}
+
+	ent, err := d.XrefEntry(ref.Number, ref.Generation)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, err := d.ReadStreamBody(ent, stream.Dict)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read %d %d R: %w", ref.Number, ref.Generation, err)
+	}
+	b, err := DecodeStreamWithLimit(stream.Dict, raw, d.decodedSizeLimit())
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to decode %d %d R: %w", ref.Number, ref.Generation, err)
+	}
+	return stream.Dict, b, nil
+}
+
+// 7.6.2 General Encryption Algorithm
+func isEncryptedStream(dict PDFDict, sec StandardSecurity) bool {
+	switch typ, _ := dict["Type"].(PDFName); typ {
+	case "XRef":
+		return false
+	case "Metadata":
+		return sec.EncryptMetadata
+	}
+	return true
+}
+
+// readStreamAt reads the stream object at offset and returns its dictionary and raw (undecoded) body.
+// /Length must be a direct object otherwise the body is delimited by endstream.
+func readStreamAt(ra io.ReaderAt, offset int64) (PDFDict, []byte, error) {
+	dict, bodyOffset, err := readStreamHeader(ra, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	length := int64(-1)
+	if n, ok := dict["Length"].(PDFInt); ok {
+		length = int64(n)
+	}
+
+	raw, err := readStreamData(ra, bodyOffset, length)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dict, raw, nil
+}
+
+// readStreamHeader reads the stream dictionary of the stream object at offset
+// and returns it with the offset where the body starts.
+func readStreamHeader(ra io.ReaderAt, offset int64) (PDFDict, int64, error) {
+	lex := NewLexer(NewAtReader(ra, offset))
+	p := newObjectParser(lex)
+
+	if _, _, err := p.readObjectHeader(); err != nil {
+		return nil, 0, fmt.Errorf("unable to read object header: %w", err)
+	}
+
+	obj, err := p.parseObject()
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to parse stream dictionary: %w", err)
+	}
+	dict, ok := obj.(PDFDict)
+	if !ok {
+		return nil, 0, errors.New("stream must start with a dictionary")
+	}
+
+	tok, err := p.next()
+	if err != nil {
+		return nil, 0, err
+	}
+	if !tok.Is(TokenKeyword, "stream") {
+		return nil, 0, errors.New("object is not a stream")
+	}
+
+	// the keyword stream shall be followed by an end-of-line marker
+	bodyOffset := offset + lex.Offset()
+	eol := make([]byte, 2)
+	if _, err := ra.ReadAt(eol, bodyOffset); err != nil && err != io.EOF {
+		return nil, 0, fmt.Errorf("unable to read stream: %w", err)
+	}
+	if eol[0] == '\r' && eol[1] == '\n' {
+		bodyOffset += 2
+	} else if eol[0] == '\n' || eol[0] == '\r' {
+		bodyOffset++
+	}
+
+	return dict, bodyOffset, nil
+}
+
+// readStreamData reads length bytes at offset.
+// When length is negative or is not followed by endstream, it searches for endstream instead.
+func readStreamData(ra io.ReaderAt, offset, length int64) ([]byte, error) {
+	length, err := streamDataLength(ra, offset, length)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, length)
+	if _, err := ra.ReadAt(raw, offset); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("unable to read stream: %w", err)
+	}
+	return raw, nil
+}
+
+// streamDataLength returns length if the data at offset is followed by endstream.
+// Otherwise it returns the length up to endstream.
+func streamDataLength(ra io.ReaderAt, offset, length int64) (int64, error) {
+	if length >= 0 && isFollowedByEndstream(ra, offset+length) {
+		return length, nil
+	}
+
+	end, err := findEndstream(ra, offset)
+	if err != nil {
+		return 0, err
+	}
+
+	// the end-of-line marker before endstream is not a part of the data
+	eol := make([]byte, 2)
+	if end-offset >= 2 {
+		if _, err := ra.ReadAt(eol, end-2); err != nil && err != io.EOF {
+			return 0, fmt.Errorf("unable to read stream: %w", err)
+		}
+	} else if end-offset == 1 {
+		if _, err := ra.ReadAt(eol[1:], end-1); err != nil && err != io.EOF {
+			return 0, fmt.Errorf("unable to read stream: %w", err)
+		}
+	}
+	if eol[1] == '\n' {
+		end--
+		if eol[0] == '\r' && end > offset {
+			end--
+		}
+	} else if eol[1] == '\r' {
+		end--
+	}
+	return end - offset, nil
+}
+
+const endstreamKeyword = "endstream"
+
+func isFollowedByEndstream(ra io.ReaderAt, offset int64) bool {
+	b := make([]byte, 32)
+	n, _ := ra.ReadAt(b, offset)
+	return bytes.HasPrefix(bytes.TrimLeft(b[:n], "\x00\t\n\f\r "), []byte(endstreamKeyword))
+}
+
+// findEndstream returns the offset of the first endstream keyword after offset.
+func findEndstream(ra io.ReaderAt, offset int64) (int64, error) {
+	const window = 4096
+	buf := make([]byte, window+len(endstreamKeyword))
+	for pos := offset; ; pos += window {
+		n, err := ra.ReadAt(buf, pos)
+		if i := bytes.Index(buf[:n], []byte(endstreamKeyword)); i >= 0 {
+			return pos + int64(i), nil
+		}
+		if err == io.EOF {
+			return 0, errors.New("unable to find endstream")
+		}
+		if err != nil {
+			return 0, fmt.Errorf("unable to read stream: %w", err)
+		}
+	}
+}
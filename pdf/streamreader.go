@@ -0,0 +1,237 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rc4"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// OpenStream returns a reader over the decoded body of the stream object at ent.
+// The body is read from the file as the reader is read. FlateDecode without a predictor is
+// decoded on the fly while the other filters decode the whole output of the previous filter at once.
+// Close closes the decompressors but not the underlying file. Document.Close closes readers left open.
+// Read returns ErrDecodedSizeExceeded when the decoded body exceeds MaxDecodedSize.
+func (d *Document) OpenStream(ent XrefEntry) (io.ReadCloser, error) {
+	ref := PDFRef{Number: ent.Number, Generation: ent.Generation}
+	if ent.Compressed {
+		return nil, fmt.Errorf("%d %d R is in an object stream and cannot be a stream", ref.Number, ref.Generation)
+	}
+
+	obj, err := d.readObject(ref)
+	if err != nil {
+		return nil, err
+	}
+	stream, ok := obj.(PDFStream)
+	if !ok {
+		return nil, fmt.Errorf("%d %d R must be a stream but got %T", ref.Number, ref.Generation, obj)
+	}
+	dict := stream.Dict
+
+	filters, parms, err := streamFilters(dict)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range filters {
+		if _, ok := filterDecoders[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedFilter, name)
+		}
+	}
+
+	offset, length, err := d.streamBody(ent, dict)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := &streamReader{r: io.NewSectionReader(d.ra, offset, length), limit: d.decodedSizeLimit()}
+
+	if d.crypt != nil && isEncryptedStream(dict, d.crypt.sec) {
+		sr.r, err = d.crypt.decryptReader(d.crypt.streamMethod(dict), ref, sr.r)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt stream: %w", err)
+		}
+	}
+
+	for i, name := range filters {
+		if err := sr.decode(name, parms[i]); err != nil {
+			sr.Close()
+			return nil, fmt.Errorf("unable to decode %s: %w", name, err)
+		}
+	}
+	sr.r = newSizeLimitReader(sr.r, sr.limit)
+
+	if d.openStreams == nil {
+		d.openStreams = map[*streamReader]struct{}{}
+	}
+	d.openStreams[sr] = struct{}{}
+	sr.doc = d
+
+	return sr, nil
+}
+
+type streamReader struct {
+	r       io.Reader
+	closers []io.Closer
+	// limit is the limit of the size of the output of each filter
+	limit int64
+	// doc tracks the reader until it is closed
+	doc *Document
+}
+
+func (sr *streamReader) Read(p []byte) (int, error) {
+	return sr.r.Read(p)
+}
+
+func (sr *streamReader) Close() error {
+	var err error
+	for i := len(sr.closers) - 1; i >= 0; i-- {
+		if cerr := sr.closers[i].Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	sr.closers = nil
+	if sr.doc != nil {
+		delete(sr.doc.openStreams, sr)
+		sr.doc = nil
+	}
+	return err
+}
+
+// decode wraps the reader with the filter.
+func (sr *streamReader) decode(name PDFName, parms PDFDict) error {
+	if name == "FlateDecode" {
+		if predictor, ok := parms["Predictor"].(PDFInt); !ok || predictor == 1 {
+			zr, err := zlib.NewReader(sr.r)
+			if err != nil {
+				return fmt.Errorf("unable to initialize zlib: %w", err)
+			}
+			sr.r = zr
+			sr.closers = append(sr.closers, zr)
+			return nil
+		}
+	}
+
+	b, err := io.ReadAll(newSizeLimitReader(sr.r, sr.limit))
+	if err != nil {
+		return err
+	}
+	b, err = filterDecoders[name](b, parms, sr.limit)
+	if err != nil {
+		return err
+	}
+	sr.r = bytes.NewReader(b)
+	return nil
+}
+
+// decryptReader is decryptBytes for a reader.
+func (h *securityHandler) decryptReader(method PDFName, ref PDFRef, r io.Reader) (io.Reader, error) {
+	switch method {
+	case "None":
+		return r, nil
+	case "V2":
+		c, err := rc4.NewCipher(h.objectKey(ref, false))
+		if err != nil {
+			return nil, err
+		}
+		return cipher.StreamReader{S: c, R: r}, nil
+	case "AESV2":
+		return newAESCBCReader(h.objectKey(ref, true), r)
+	case "AESV3":
+		return newAESCBCReader(h.key, r)
+	}
+	return nil, fmt.Errorf("%w: crypt filter method /%s", ErrUnsupportedEncryption, method)
+}
+
+// aesCBCReader is aesCBCDecrypt for a reader.
+// The last block is held back until the end of input to remove the padding.
+type aesCBCReader struct {
+	r     io.Reader
+	block cipher.Block
+	mode  cipher.BlockMode
+
+	// out is decrypted data ready to be read
+	out []byte
+	// last is the last decrypted block which may have the padding
+	last []byte
+	err  error
+}
+
+func newAESCBCReader(key []byte, r io.Reader) (*aesCBCReader, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return &aesCBCReader{r: r, block: block}, nil
+}
+
+func (ar *aesCBCReader) Read(p []byte) (int, error) {
+	for len(ar.out) == 0 && ar.err == nil {
+		ar.fill()
+	}
+	if len(ar.out) > 0 {
+		n := copy(p, ar.out)
+		ar.out = ar.out[n:]
+		return n, nil
+	}
+	return 0, ar.err
+}
+
+func (ar *aesCBCReader) fill() {
+	if ar.mode == nil {
+		// the first block is the IV
+		iv := make([]byte, aes.BlockSize)
+		n, err := io.ReadFull(ar.r, iv)
+		if n == 0 && err == io.EOF {
+			ar.err = io.EOF
+			return
+		}
+		if err != nil {
+			ar.err = fmt.Errorf("invalid length of AES encrypted data: %w", err)
+			return
+		}
+		ar.mode = cipher.NewCBCDecrypter(ar.block, iv)
+	}
+
+	chunk := make([]byte, 256*aes.BlockSize)
+	n, err := io.ReadFull(ar.r, chunk)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		ar.err = err
+		return
+	}
+	if n%aes.BlockSize != 0 {
+		ar.err = errors.New("invalid length of AES encrypted data")
+		return
+	}
+	ar.mode.CryptBlocks(chunk[:n], chunk[:n])
+
+	data := append(ar.last, chunk[:n]...)
+	if err == nil {
+		// more data may follow
+		split := len(data) - aes.BlockSize
+		ar.out, ar.last = data[:split], data[split:]
+		return
+	}
+
+	// 7.6.3.2 the padding is always there
+	if len(data) == 0 {
+		ar.err = errors.New("invalid length of AES encrypted data")
+		return
+	}
+	pad := int(data[len(data)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		ar.err = errors.New("invalid AES padding")
+		return
+	}
+	for _, c := range data[len(data)-pad:] {
+		if int(c) != pad {
+			ar.err = errors.New("invalid AES padding")
+			return
+		}
+	}
+	ar.out, ar.last = data[:len(data)-pad], nil
+	ar.err = io.EOF
+}
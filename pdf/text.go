@@ -0,0 +1,348 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PageText returns the text shown by the content streams of page.
+// Strings are decoded with /ToUnicode of the font if any. Otherwise they are decoded with the font encoding
+// when it is WinAnsiEncoding or StandardEncoding and are copied as-is. Codes of a Type0 font are split by its CMap
+// and unmapped ones are U+FFFD. Lines are separated when the text moves to the next line.
+func (d *Document) PageText(page PDFDict) (string, error) {
+	return d.PageTextContext(context.Background(), page)
+}
+
+// PageTextContext is the same as PageText but aborts interpreting the content streams when ctx is done.
+func (d *Document) PageTextContext(ctx context.Context, page PDFDict) (string, error) {
+	content, err := d.pageContents(page)
+	if err != nil {
+		return "", err
+	}
+
+	fonts, err := d.pageTextFonts(page)
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	var font PDFName
+
+	showText := func(obj PDFObject) {
+		s, ok := obj.(PDFString)
+		if !ok {
+			return
+		}
+		text.WriteString(fonts[font].decode(s))
+	}
+	newLine := func() {
+		if text.Len() > 0 && !strings.HasSuffix(text.String(), "\n") {
+			text.WriteByte('\n')
+		}
+	}
+
+	ops, err := ParseContentStreamContext(ctx, content)
+	if err != nil {
+		return "", err
+	}
+
+	for _, op := range ops {
+		operands := op.Operands
+
+		// 9.4 Text Objects
+		switch op.Operator {
+		case "Tf":
+			if len(operands) == 2 {
+				font, _ = operands[0].(PDFName)
+			}
+		case "Tj":
+			if len(operands) == 1 {
+				showText(operands[0])
+			}
+		case "TJ":
+			if len(operands) == 1 {
+				arr, _ := operands[0].(PDFArray)
+				for _, obj := range arr {
+					// a large negative adjustment is usually a space between words
+					if adj, ok := toFloat(obj); ok && adj <= -250 {
+						text.WriteByte(' ')
+						continue
+					}
+					showText(obj)
+				}
+			}
+		case "'":
+			newLine()
+			if len(operands) == 1 {
+				showText(operands[0])
+			}
+		case "\"":
+			newLine()
+			if len(operands) == 3 {
+				showText(operands[2])
+			}
+		case "T*":
+			newLine()
+		case "Td", "TD":
+			if len(operands) == 2 {
+				if ty, ok := toFloat(operands[1]); ok && ty != 0 {
+					newLine()
+				}
+			}
+		case "ET":
+			newLine()
+		}
+	}
+
+	return text.String(), nil
+}
+
+// pageContents returns the concatenated content streams of page.
+// 7.8.2 Content Streams: the division between streams may occur only at token boundaries.
+func (d *Document) pageContents(page PDFDict) ([]byte, error) {
+	var refs []PDFRef
+	switch contents := page["Contents"].(type) {
+	case nil:
+		return nil, nil
+	case PDFRef:
+		obj, err := d.Resolve(contents)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve /Contents: %w", err)
+		}
+		if arr, ok := obj.(PDFArray); ok {
+			for _, v := range arr {
+				ref, ok := v.(PDFRef)
+				if !ok {
+					return nil, errors.New("/Contents must be an array of references")
+				}
+				refs = append(refs, ref)
+			}
+		} else {
+			refs = append(refs, contents)
+		}
+	case PDFArray:
+		for _, v := range contents {
+			ref, ok := v.(PDFRef)
+			if !ok {
+				return nil, errors.New("/Contents must be an array of references")
+			}
+			refs = append(refs, ref)
+		}
+	default:
+		return nil, fmt.Errorf("/Contents must be a reference or an array but got %T", contents)
+	}
+
+	var content []byte
+	for _, ref := range refs {
+		_, b, err := d.readStream(ref)
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, b...)
+		content = append(content, '\n')
+	}
+	return content, nil
+}
+
+// textFont has what is needed to decode strings shown with a font.
+type textFont struct {
+	// encoding is the base encoding
+	encoding  PDFName
+	toUnicode *toUnicodeCMap
+
+	// composite is true for a Type0 font whose codes are split by codespaces.
+	// Codes are 2 bytes long when codespaces is empty as in Identity-H and Identity-V.
+	composite  bool
+	codespaces []codespaceRange
+	// utf16 is true when codes are UTF-16BE as in UniJIS-UCS2-H
+	utf16 bool
+
+	// widths are keyed by the code or the CID. See FontWidths.
+	widths       map[int]float64
+	defaultWidth float64
+}
+
+// decode decodes s into UTF-8. f may be nil for an unknown font.
+func (f *textFont) decode(s []byte) string {
+	if f == nil {
+		return string(s)
+	}
+	if f.composite {
+		return f.decodeComposite(s)
+	}
+
+	simple := func(code []byte) string {
+		if decoded, ok := decodeSimple(f.encoding, code); ok {
+			return decoded
+		}
+		return string(code)
+	}
+	if f.toUnicode != nil {
+		return f.toUnicode.decode(s, simple)
+	}
+	return simple(s)
+}
+
+// decodeComposite decodes codes of a Type0 font.
+// A code which cannot be mapped to Unicode is U+FFFD since a CID is not a character.
+func (f *textFont) decodeComposite(s []byte) string {
+	var out []byte
+	for _, code := range f.splitCodes(s) {
+		n := len(code)
+		if f.toUnicode != nil {
+			if u, ok := f.toUnicode.mappings[cmapCode{cmapCodeOf(string(code)), n}]; ok {
+				out = append(out, u...)
+				continue
+			}
+		}
+		if f.utf16 {
+			out = append(out, utf16BEString(string(code))...)
+			continue
+		}
+		out = append(out, "\ufffd"...)
+	}
+	return string(out)
+}
+
+// splitCodes splits s into character codes. A code of a simple font is a byte.
+func (f *textFont) splitCodes(s []byte) [][]byte {
+	var codes [][]byte
+	for i := 0; i < len(s); {
+		n := 1
+		if f != nil && f.composite {
+			var ok bool
+			if n, ok = codeLength(f.codespaces, s[i:]); !ok {
+				n = 2
+			}
+			if i+n > len(s) {
+				n = len(s) - i
+			}
+		}
+		codes = append(codes, s[i:i+n])
+		i += n
+	}
+	return codes
+}
+
+// width returns the width of the glyph of code in 1/1000 of text space units.
+// The code of a composite font is used as the CID as in Identity-H.
+func (f *textFont) width(code []byte) float64 {
+	if f == nil {
+		return 0
+	}
+	if w, ok := f.widths[int(cmapCodeOf(string(code)))]; ok {
+		return w
+	}
+	return f.defaultWidth
+}
+
+// pageTextFonts returns the base encoding and the ToUnicode CMap of each font in the resources of page.
+func (d *Document) pageTextFonts(page PDFDict) (map[PDFName]*textFont, error) {
+	fonts, err := d.PageFonts(page)
+	if err != nil {
+		return nil, err
+	}
+
+	textFonts := map[PDFName]*textFont{}
+	for name, font := range fonts {
+		f := &textFont{}
+
+		enc, err := d.Resolve(font["Encoding"])
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve /Encoding of font /%s: %w", name, err)
+		}
+		if subtype, _ := font["Subtype"].(PDFName); subtype == "Type0" {
+			// 9.7.6.2 /Encoding of a Type0 font is a CMap name or an embedded CMap stream
+			f.composite = true
+			switch enc := enc.(type) {
+			case PDFName:
+				f.utf16 = strings.Contains(string(enc), "-UCS2-") || strings.Contains(string(enc), "-UTF16-")
+			case PDFStream:
+				ref, _ := font["Encoding"].(PDFRef)
+				if _, b, err := d.readStream(ref); err == nil {
+					// only codespace ranges are used to split codes
+					if cmap, err := parseToUnicodeCMap(b); err == nil {
+						f.codespaces = cmap.codespaces
+					}
+				}
+			}
+		} else {
+			switch enc := enc.(type) {
+			case PDFName:
+				f.encoding = enc
+			case PDFDict:
+				// 9.6.6.1 Encoding dictionary without /Differences
+				if _, ok := enc["Differences"]; !ok {
+					f.encoding, _ = enc["BaseEncoding"].(PDFName)
+				}
+			}
+		}
+
+		// 9.10.2 /ToUnicode takes precedence over the encoding
+		if ref, ok := font["ToUnicode"].(PDFRef); ok {
+			_, b, err := d.readStream(ref)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read /ToUnicode of font /%s: %w", name, err)
+			}
+			// a broken CMap falls back to the encoding
+			if cmap, err := parseToUnicodeCMap(b); err == nil {
+				f.toUnicode = cmap
+			}
+		}
+
+		// widths are only for positions so broken ones are ignored
+		f.widths, _ = d.FontWidths(font)
+		f.defaultWidth, _ = d.FontDefaultWidth(font)
+
+		textFonts[PDFName(name)] = f
+	}
+	return textFonts, nil
+}
+
+// resolveDict resolves obj into a dictionary. A missing object is an empty dictionary.
+func (d *Document) resolveDict(obj PDFObject) (PDFDict, error) {
+	if obj == nil {
+		return PDFDict{}, nil
+	}
+	resolved, err := d.Resolve(obj)
+	if err != nil {
+		return nil, err
+	}
+	switch resolved := resolved.(type) {
+	case PDFDict:
+		return resolved, nil
+	case PDFNull:
+		return PDFDict{}, nil
+	}
+	return nil, fmt.Errorf("expected a dictionary but got %T", resolved)
+}
+
+// resolveArray resolves obj into an array. A missing object is an empty array.
+func (d *Document) resolveArray(obj PDFObject) (PDFArray, error) {
+	if obj == nil {
+		return PDFArray{}, nil
+	}
+	resolved, err := d.Resolve(obj)
+	if err != nil {
+		return nil, err
+	}
+	switch resolved := resolved.(type) {
+	case PDFArray:
+		return resolved, nil
+	case PDFNull:
+		return PDFArray{}, nil
+	}
+	return nil, fmt.Errorf("expected an array but got %T", resolved)
+}
+
+func toFloat(obj PDFObject) (float64, bool) {
+	switch n := obj.(type) {
+	case PDFInt:
+		return float64(n), true
+	case PDFReal:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,168 @@
+package pdf
+
+// TextRun is text shown by a text-showing operator.
+// X and Y are the start of the text in the default user space and FontSize is the font size scaled into it.
+type TextRun struct {
+	Text     string
+	X, Y     float64
+	FontSize float64
+}
+
+// 9.3 Text State Parameters and Operators
+type textState struct {
+	font     PDFName
+	fontSize float64
+	// charSpacing is Tc, wordSpacing is Tw, scale is Tz / 100, leading is TL and rise is Ts
+	charSpacing float64
+	wordSpacing float64
+	scale       float64
+	leading     float64
+	rise        float64
+}
+
+// PageTextRuns returns text shown by the content streams of page with the positions.
+// Strings are decoded as PageText does. Each of Tj, TJ, ' and " is a run and the text moves by
+// the glyph widths of the font after each string. A large negative adjustment in TJ is a space.
+func (d *Document) PageTextRuns(page PDFDict) ([]TextRun, error) {
+	content, err := d.pageContents(page)
+	if err != nil {
+		return nil, err
+	}
+	fonts, err := d.pageTextFonts(page)
+	if err != nil {
+		return nil, err
+	}
+	ops, err := ParseContentStream(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// the text state is a part of the graphics state
+	gs := newGraphicsStack()
+
+	// 9.4.2 the text matrix and the text line matrix
+	tm, tlm := IdentityMatrix, IdentityMatrix
+
+	var runs []TextRun
+	var run *TextRun
+
+	startRun := func() {
+		trm := tm.Multiply(gs.ctm)
+		x, y := trm.Transform(0, gs.text.rise)
+		runs = append(runs, TextRun{X: x, Y: y, FontSize: gs.text.fontSize * trm.yScale()})
+		run = &runs[len(runs)-1]
+	}
+	// 9.4.4 Text Space Details
+	showText := func(obj PDFObject) {
+		s, ok := obj.(PDFString)
+		if !ok {
+			return
+		}
+		font := fonts[gs.text.font]
+		run.Text += font.decode(s)
+		for _, code := range font.splitCodes(s) {
+			tx := font.width(code)/1000*gs.text.fontSize + gs.text.charSpacing
+			// word spacing applies to the single-byte code 32
+			if len(code) == 1 && code[0] == ' ' {
+				tx += gs.text.wordSpacing
+			}
+			tm = translateMatrix(tx*gs.text.scale, 0).Multiply(tm)
+		}
+	}
+	moveText := func(tx, ty float64) {
+		tlm = translateMatrix(tx, ty).Multiply(tlm)
+		tm = tlm
+	}
+	nextLine := func() {
+		moveText(0, -gs.text.leading)
+	}
+
+	for _, op := range ops {
+		if gs.apply(op) {
+			continue
+		}
+
+		operands := op.Operands
+		nums := make([]float64, len(operands))
+		for i, obj := range operands {
+			nums[i], _ = toFloat(obj)
+		}
+
+		switch op.Operator {
+		case "BT":
+			tm, tlm = IdentityMatrix, IdentityMatrix
+		case "Tf":
+			if len(operands) == 2 {
+				gs.text.font, _ = operands[0].(PDFName)
+				gs.text.fontSize = nums[1]
+			}
+		case "Tc", "Tw", "Tz", "TL", "Ts":
+			if len(operands) != 1 {
+				continue
+			}
+			switch op.Operator {
+			case "Tc":
+				gs.text.charSpacing = nums[0]
+			case "Tw":
+				gs.text.wordSpacing = nums[0]
+			case "Tz":
+				gs.text.scale = nums[0] / 100
+			case "TL":
+				gs.text.leading = nums[0]
+			case "Ts":
+				gs.text.rise = nums[0]
+			}
+		case "Td":
+			if len(operands) == 2 {
+				moveText(nums[0], nums[1])
+			}
+		case "TD":
+			if len(operands) == 2 {
+				gs.text.leading = -nums[1]
+				moveText(nums[0], nums[1])
+			}
+		case "Tm":
+			if m, ok := matrixOf(operands); ok {
+				tm, tlm = m, m
+			}
+		case "T*":
+			nextLine()
+		case "Tj":
+			if len(operands) == 1 {
+				startRun()
+				showText(operands[0])
+			}
+		case "'":
+			if len(operands) == 1 {
+				nextLine()
+				startRun()
+				showText(operands[0])
+			}
+		case "\"":
+			if len(operands) == 3 {
+				gs.text.wordSpacing, gs.text.charSpacing = nums[0], nums[1]
+				nextLine()
+				startRun()
+				showText(operands[2])
+			}
+		case "TJ":
+			if len(operands) != 1 {
+				continue
+			}
+			arr, _ := operands[0].(PDFArray)
+			startRun()
+			for _, obj := range arr {
+				adj, ok := toFloat(obj)
+				if !ok {
+					showText(obj)
+					continue
+				}
+				if adj <= -250 {
+					run.Text += " "
+				}
+				tm = translateMatrix(-adj/1000*gs.text.fontSize*gs.text.scale, 0).Multiply(tm)
+			}
+		}
+	}
+	return runs, nil
+}
@@ -0,0 +1,83 @@
+package pdf
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// DecodeTextString decodes a text string into UTF-8.
+// b is in UTF-16BE or UTF-8 when it starts with the byte order mark and in PDFDocEncoding otherwise.
+// Language escape codes in UTF-16BE are removed.
+// 7.9.2.2 Text String Type
+func DecodeTextString(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xfe && b[1] == 0xff {
+		units := make([]uint16, 0, (len(b)-2)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		return removeLanguageCodes(string(utf16.Decode(units)))
+	}
+	if len(b) >= 3 && b[0] == 0xef && b[1] == 0xbb && b[2] == 0xbf {
+		// PDF 2.0
+		return strings.ToValidUTF8(string(b[3:]), "\ufffd")
+	}
+
+	rs := make([]rune, 0, len(b))
+	for _, c := range b {
+		if r, ok := pdfDocEncoding[c]; ok {
+			rs = append(rs, r)
+			continue
+		}
+		rs = append(rs, rune(c))
+	}
+	return string(rs)
+}
+
+// EncodeTextString encodes s into a text string.
+// s is written as-is if it has only printable ASCII characters and in UTF-16BE with the byte order mark otherwise.
+func EncodeTextString(s string) PDFString {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return PDFString(s)
+	}
+
+	b := []byte{0xfe, 0xff}
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = append(b, byte(u>>8), byte(u))
+	}
+	return PDFString(b)
+}
+
+// Annex D.3 PDFDocEncoding Character Set
+// Only codes that differ from ISO Latin-1 are listed. Undefined codes are U+FFFD.
+var pdfDocEncoding = map[byte]rune{
+	0x18: '˘', 0x19: 'ˇ', 0x1a: 'ˆ', 0x1b: '˙', 0x1c: '˝', 0x1d: '˛', 0x1e: '˚', 0x1f: '˜',
+	0x7f: '�',
+	0x80: '•', 0x81: '†', 0x82: '‡', 0x83: '…', 0x84: '—', 0x85: '–', 0x86: 'ƒ', 0x87: '⁄',
+	0x88: '‹', 0x89: '›', 0x8a: '−', 0x8b: '‰', 0x8c: '„', 0x8d: '“', 0x8e: '”', 0x8f: '‘',
+	0x90: '’', 0x91: '‚', 0x92: '™', 0x93: 'ﬁ', 0x94: 'ﬂ', 0x95: 'Ł', 0x96: 'Œ', 0x97: 'Š',
+	0x98: 'Ÿ', 0x99: 'Ž', 0x9a: 'ı', 0x9b: 'ł', 0x9c: 'œ', 0x9d: 'š', 0x9e: 'ž', 0x9f: '�',
+	0xa0: '€', 0xad: '�',
+}
+
+// removeLanguageCodes removes language codes enclosed by U+001B.
+// 7.9.2.2.1 General
+func removeLanguageCodes(s string) string {
+	for {
+		start := strings.IndexRune(s, 0x1b)
+		if start < 0 {
+			return s
+		}
+		end := strings.IndexRune(s[start+1:], 0x1b)
+		if end < 0 {
+			return s
+		}
+		s = s[:start] + s[start+1+end+1:]
+	}
+}
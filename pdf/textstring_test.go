@@ -0,0 +1,60 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestDecodeTextString(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+	}{
+		{in: "Plain title", want: "Plain title"},
+		// PDFDocEncoding differs from Latin-1 in 0x18-0x1f and 0x80-0xa0
+		{in: "\x93le \x84 \x8dquoted\x8e \xa0 caf\xe9", want: "ﬁle — “quoted” € café"},
+		{in: "\xfe\xff\x00H\x00i", want: "Hi"},
+		{in: "\xfe\xff\x65\xe5\x67\x2c\x8a\x9e", want: "日本語"},
+		// a surrogate pair
+		{in: "\xfe\xff\xd8\x3d\xdc\xc4", want: "📄"},
+		// a language code
+		{in: "\xfe\xff\x00\x1b\x00j\x00a\x00\x1b\x65\xe5\x67\x2c", want: "日本"},
+		{in: "\xef\xbb\xbf\xce\x95\xce\xbb", want: "Ελ"},
+		{in: "\xfe\xff", want: ""},
+	} {
+		if got := DecodeTextString([]byte(tc.in)); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestTextStringRoundTrip(t *testing.T) {
+	for _, s := range []string{"Plain title", "日本語のタイトル", "Ελληνικά και русский", "Report 📄 2024", "café"} {
+		enc := EncodeTextString(s)
+		if got := DecodeTextString(enc); got != s {
+			t.Errorf("%q: got %q from %X", s, got, []byte(enc))
+		}
+	}
+
+	// a UTF-16BE title written as a hexadecimal string in /Info
+	title := "日本語のタイトル — Ελληνικά 📄"
+	b := []byte{0xfe, 0xff}
+	for _, u := range utf16.Encode([]rune(title)) {
+		b = append(b, byte(u>>8), byte(u))
+	}
+	pdf := buildXrefStreamPDF(map[int64]string{
+		1: "<< /Type /Catalog >>",
+		2: fmt.Sprintf("<< /Title <%X> >>", b),
+	}, nil)
+	pdf = bytes.Replace(pdf, []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Info 2 0 R"), 1)
+
+	info, err := openBytes(t, pdf).Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Title != title {
+		t.Errorf("got %q, want %q", info.Title, title)
+	}
+}
@@ -0,0 +1,176 @@
+package pdf
+
+import (
+	"bufio"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// AppendUpdate writes the original file followed by an incremental update of the changed objects to w.
+// 7.5.6 Incremental Updates
+// The update has a cross-reference section covering only the changed objects and a trailer whose /Prev is
+// the previous startxref so that the original bytes including signatures are kept intact.
+// A nil object frees the object number. An object number without an entry adds a new object.
+// When the set of free objects changes, all free entries are written again to link the new free list.
+// The second element of /ID is regenerated.
+// Streams cannot be changed since PDFStream does not hold the body. Encrypted documents are not supported.
+func (d *Document) AppendUpdate(w io.Writer, changed map[int64]PDFObject) error {
+	encrypted, _, err := d.IsEncrypted()
+	if err != nil {
+		return err
+	}
+	if encrypted {
+		return errors.New("unable to update an encrypted document")
+	}
+	if d.trailerDict == nil {
+		return errors.New("unable to update a document without cross-reference sections")
+	}
+
+	numbers := make([]int64, 0, len(changed))
+	for number, obj := range changed {
+		if number <= 0 {
+			return fmt.Errorf("invalid object number: %d", number)
+		}
+		if _, ok := obj.(PDFStream); ok {
+			return fmt.Errorf("unable to update stream %d", number)
+		}
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	size := d.trailer.size
+	if _, err := io.Copy(cw, io.NewSectionReader(d.ra, 0, size)); err != nil {
+		return fmt.Errorf("unable to copy the original: %w", err)
+	}
+	if size > 0 {
+		last := make([]byte, 1)
+		if _, err := d.ra.ReadAt(last, size-1); err == nil && last[0] != '\n' && last[0] != '\r' {
+			fmt.Fprint(cw, "\n")
+		}
+	}
+
+	// offsets in the file are relative to the header
+	base := d.trailer.base
+
+	generations := make(map[int64]int, len(d.entries))
+	for _, ent := range d.entries {
+		generations[ent.Number] = ent.Generation
+	}
+
+	entries := make([]XrefEntry, 0, len(numbers))
+	maxNumber := int64(0)
+	freeListChanged := false
+	for _, number := range numbers {
+		if number > maxNumber {
+			maxNumber = number
+		}
+
+		ent := XrefEntry{Number: number}
+		prevGeneration, exists := generations[number]
+		if prev, ok := d.entryOf(number); ok && !prev.InUse {
+			// a free object is reused
+			freeListChanged = true
+		}
+
+		obj := changed[number]
+		if obj == nil {
+			freeListChanged = true
+			// 7.5.4 the generation is incremented when the object is freed
+			if exists {
+				ent.Generation = prevGeneration + 1
+			}
+			entries = append(entries, ent)
+			continue
+		}
+
+		// 7.5.4 a free entry has the generation for the next use of the number
+		if exists {
+			ent.Generation = prevGeneration
+		}
+		ent.InUse = true
+		ent.ByteOffset = cw.n - base
+		fmt.Fprintf(cw, "%d %d obj\n%s\nendobj\n", number, ent.Generation, renderObject(obj))
+		entries = append(entries, ent)
+	}
+
+	if freeListChanged {
+		// 7.5.4 free entries are linked by the number of the next free object from entry 0
+		// and the last one has 0. Entry 0 has generation 65535 as it is never reused.
+		inSection := make(map[int64]bool, len(entries))
+		for _, ent := range entries {
+			inSection[ent.Number] = true
+		}
+		for _, ent := range d.entries {
+			if !ent.InUse && ent.Number != 0 && !inSection[ent.Number] {
+				entries = append(entries, XrefEntry{Number: ent.Number, Generation: ent.Generation})
+			}
+		}
+		entries = append(entries, XrefEntry{Number: 0, Generation: 65535})
+		sortXrefEntries(entries)
+
+		next := int64(0)
+		for i := len(entries) - 1; i >= 0; i-- {
+			if !entries[i].InUse {
+				entries[i].ByteOffset = next
+				next = entries[i].Number
+			}
+		}
+	}
+
+	xrefOffset := cw.n - base
+	fmt.Fprint(cw, "xref\n")
+	for i := 0; i < len(entries); {
+		// a subsection for each run of consecutive numbers
+		j := i + 1
+		for j < len(entries) && entries[j].Number == entries[j-1].Number+1 {
+			j++
+		}
+		fmt.Fprintf(cw, "%d %d\n", entries[i].Number, j-i)
+		for _, ent := range entries[i:j] {
+			if ent.InUse {
+				fmt.Fprintf(cw, "%010d %05d n\r\n", ent.ByteOffset, ent.Generation)
+			} else {
+				fmt.Fprintf(cw, "%010d %05d f\r\n", ent.ByteOffset, ent.Generation)
+			}
+		}
+		i = j
+	}
+
+	// 7.5.6 the trailer has all entries of the previous trailer except /Prev.
+	// Entries of a cross-reference stream dictionary are not copied.
+	trailerSize := int64(0)
+	if n, ok := d.trailerDict["Size"].(PDFInt); ok {
+		trailerSize = int64(n)
+	}
+	if maxNumber+1 > trailerSize {
+		trailerSize = maxNumber + 1
+	}
+	fmt.Fprintf(cw, "trailer\n<<\n/Size %d\n", trailerSize)
+	for _, key := range []string{"Root", "Info"} {
+		if v, ok := d.trailerDict[key]; ok {
+			fmt.Fprintf(cw, "%s %s\n", PDFName(key), renderObject(v))
+		}
+	}
+	// 14.4 the first identifier is permanent and the second one is changed by an update
+	if ids, err := fileID(d.trailerDict); err == nil {
+		h := md5.New()
+		fmt.Fprintf(h, "%d %d %d ", time.Now().UnixNano(), size, xrefOffset)
+		h.Write(ids[1])
+		fmt.Fprintf(cw, "/ID %s\n", PDFArray{PDFString(ids[0]), PDFString(h.Sum(nil))})
+	} else if v, ok := d.trailerDict["ID"]; ok {
+		// a malformed /ID is kept as it is
+		fmt.Fprintf(cw, "/ID %s\n", renderObject(v))
+	}
+	fmt.Fprintf(cw, "/Prev %d\n>>\nstartxref\n%d\n%%%%EOF\n", d.trailer.StartXref-base, xrefOffset)
+
+	if cw.err != nil {
+		return fmt.Errorf("unable to write: %w", cw.err)
+	}
+	return cw.w.Flush()
+}
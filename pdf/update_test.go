@@ -0,0 +1,255 @@
+package pdf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestAppendUpdate(t *testing.T) {
+	classic := bytes.Replace(buildClassicPDF(3), []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Info 3 0 R"), 1)
+	xrefStream := bytes.Replace(buildXrefStreamPDF(map[int64]string{
+		1: "<< /Type /Catalog >>",
+		3: "<< /Title (Original title) /Producer (test) >>",
+	}, nil), []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Info 3 0 R"), 1)
+
+	for _, tc := range []struct {
+		name     string
+		original []byte
+		title    string
+		producer string
+	}{
+		{name: "xref table", original: classic},
+		{name: "xref stream", original: xrefStream, title: "Original title", producer: "test"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := tc.original
+			titles := []string{tc.title}
+			for _, title := range []string{"First update", "日本語のタイトル"} {
+				d := openBytes(t, b)
+				info, err := d.GetDict(3, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				updated := PDFDict{}
+				for k, v := range info {
+					updated[k] = v
+				}
+				updated["Title"] = EncodeTextString(title)
+
+				var out bytes.Buffer
+				if err := d.AppendUpdate(&out, map[int64]PDFObject{3: updated}); err != nil {
+					t.Fatal(err)
+				}
+				// the original bytes are kept intact
+				if !bytes.HasPrefix(out.Bytes(), b) {
+					t.Fatal("the update does not start with the original")
+				}
+
+				// /Prev is the previous startxref and the new section points to the objects
+				prev, err := ReadTrailer(bytes.NewReader(b), int64(len(b)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				tr, err := ReadTrailer(bytes.NewReader(out.Bytes()), int64(out.Len()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := tr.Dict["Prev"]; got != PDFInt(prev.StartXref) {
+					t.Errorf("got /Prev %v, want %d", got, prev.StartXref)
+				}
+				if tr.StartXref <= int64(len(b)) {
+					t.Errorf("startxref %d is not in the update", tr.StartXref)
+				}
+				section, err := tr.ListXrefEntries()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(section) != 1 || section[0].Number != 3 || !section[0].InUse {
+					t.Fatalf("got %+v, want the entry of 3 0 R", section)
+				}
+				entry, err := readEntry(section[0], bytes.NewReader(out.Bytes()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.HasPrefix(entry, []byte("3 0 obj")) {
+					t.Errorf("got %q at the offset of 3 0 R", entry)
+				}
+
+				b = out.Bytes()
+				titles = append(titles, title)
+			}
+
+			d := openBytes(t, b)
+			info, err := d.Info()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := titles[len(titles)-1]; info.Title != want {
+				t.Errorf("got %q, want %q", info.Title, want)
+			}
+			// the other entries are kept
+			if info.Producer != tc.producer {
+				t.Errorf("got /Producer %q, want %q", info.Producer, tc.producer)
+			}
+
+			revisions, err := d.Revisions()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(revisions) != len(titles) {
+				t.Fatalf("got %d revisions, want %d", len(revisions), len(titles))
+			}
+			for i := 1; i < len(revisions); i++ {
+				if got := revisions[i].Trailer["Prev"]; got != PDFInt(revisions[i-1].XrefOffset) {
+					t.Errorf("revision %d: got /Prev %v, want %d", i, got, revisions[i-1].XrefOffset)
+				}
+			}
+
+			// every revision of /Info reads back with its own title
+			history, err := d.ObjectRevisions(3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) != len(titles) {
+				t.Fatalf("got %d revisions of 3 0 R, want %d", len(history), len(titles))
+			}
+			for i, ent := range history {
+				entry, err := d.ReadEntry(ent)
+				if err != nil {
+					t.Fatal(err)
+				}
+				obj, err := ParseObject(entry)
+				if err != nil {
+					t.Fatal(err)
+				}
+				dict, _ := obj.(PDFDict)
+				s, _ := dict["Title"].(PDFString)
+				if got := DecodeTextString(s); got != titles[i] {
+					t.Errorf("revision %d: got %q, want %q", i, got, titles[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAppendUpdateFree(t *testing.T) {
+	d := openBytes(t, buildClassicPDF(3))
+	var out bytes.Buffer
+	if err := d.AppendUpdate(&out, map[int64]PDFObject{2: nil, 5: PDFDict{"New": PDFBool(true)}}); err != nil {
+		t.Fatal(err)
+	}
+
+	d = openBytes(t, out.Bytes())
+	if ent, err := d.XrefEntry(2, 1); err != nil || ent.InUse {
+		t.Errorf("got %+v, %v, want 2 1 R freed", ent, err)
+	}
+	obj, err := d.GetObject(5, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict, _ := obj.(PDFDict); dict["New"] != PDFBool(true) {
+		t.Errorf("got %v", obj)
+	}
+	if got := d.TrailerDict()["Size"]; got != PDFInt(6) {
+		t.Errorf("got /Size %v, want 6", got)
+	}
+}
+
+func TestAppendUpdateFreeList(t *testing.T) {
+	b := buildClassicPDF(5)
+	for _, tc := range []struct {
+		name    string
+		changed map[int64]PDFObject
+		// free is the linked list from entry 0 and section is the numbers in the new section
+		free    []int64
+		section []int64
+	}{
+		{name: "free 3", changed: map[int64]PDFObject{3: nil}, free: []int64{3}, section: []int64{0, 3}},
+		{name: "free 2 and 5", changed: map[int64]PDFObject{2: nil, 5: nil}, free: []int64{2, 3, 5}, section: []int64{0, 2, 3, 5}},
+		{name: "reuse 3", changed: map[int64]PDFObject{3: PDFDict{"Reused": PDFBool(true)}}, free: []int64{2, 5}, section: []int64{0, 2, 3, 5}},
+		{name: "no change of the free list", changed: map[int64]PDFObject{4: PDFDict{}}, free: []int64{2, 5}, section: []int64{4}},
+	} {
+		var out bytes.Buffer
+		if err := openBytes(t, b).AppendUpdate(&out, tc.changed); err != nil {
+			t.Fatal(err)
+		}
+		b = out.Bytes()
+
+		tr, err := ReadTrailer(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		section, err := tr.ListXrefEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var numbers []int64
+		for _, ent := range section {
+			numbers = append(numbers, ent.Number)
+		}
+		if !reflect.DeepEqual(numbers, tc.section) {
+			t.Errorf("%s: got the section of %v, want %v", tc.name, numbers, tc.section)
+		}
+
+		// follow the list from entry 0 in the merged entries
+		d := openBytes(t, b)
+		var free []int64
+		ent, err := d.XrefEntry(0, 65535)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for ent.ByteOffset != 0 && len(free) < 10 {
+			next, ok := d.entryOf(ent.ByteOffset)
+			if !ok || next.InUse {
+				t.Fatalf("%s: %d links to %+v", tc.name, ent.Number, next)
+			}
+			free = append(free, next.Number)
+			ent = next
+		}
+		if !reflect.DeepEqual(free, tc.free) {
+			t.Errorf("%s: got the free list %v, want %v", tc.name, free, tc.free)
+		}
+	}
+	// the generation is incremented each time the number is freed
+	d := openBytes(t, b)
+	if _, err := d.XrefEntry(3, 1); err != nil {
+		t.Error(err)
+	}
+	if ent, err := d.XrefEntry(2, 1); err != nil || ent.InUse {
+		t.Errorf("got %+v, %v", ent, err)
+	}
+}
+
+func TestAppendUpdateID(t *testing.T) {
+	d := openBytes(t, bytes.Replace(buildClassicPDF(2), []byte("/Root 1 0 R"), []byte("/Root 1 0 R /ID [<0102> <0304>]"), 1))
+	var out bytes.Buffer
+	if err := d.AppendUpdate(&out, map[int64]PDFObject{2: PDFDict{}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := ReadTrailer(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := tr.ID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 14.4 the first identifier is kept and the second one is changed
+	if !bytes.Equal(ids[0], []byte{1, 2}) {
+		t.Errorf("got the first identifier %X, want 0102", ids[0])
+	}
+	if bytes.Equal(ids[1], []byte{3, 4}) || len(ids[1]) != 16 {
+		t.Errorf("got the second identifier %X, want a new one", ids[1])
+	}
+
+	// a document without /ID is updated without /ID
+	out.Reset()
+	if err := openBytes(t, buildClassicPDF(2)).AppendUpdate(&out, map[int64]PDFObject{2: PDFDict{}}); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(out.Bytes(), []byte("/ID")) {
+		t.Error("/ID is added")
+	}
+}
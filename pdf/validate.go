@@ -0,0 +1,47 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrMissingEOF          = errors.New("file does not end with %%EOF")
+	ErrStartxrefOutOfRange = errors.New("startxref is out of range")
+	ErrInvalidXrefStart    = errors.New("startxref does not point to xref or a cross-reference stream")
+)
+
+// Validate checks the file ends with %%EOF and startxref points to a cross-reference section.
+func (t Trailer) Validate() error {
+	tail := make([]byte, 1024)
+	if int64(len(tail)) > t.size {
+		tail = tail[:t.size]
+	}
+	if _, err := t.ra.ReadAt(tail, t.size-int64(len(tail))); err != nil {
+		return fmt.Errorf("unable to read the end of file: %w", err)
+	}
+	if !bytes.HasSuffix(bytes.TrimRight(tail, "\x00\t\n\f\r "), []byte("%%EOF")) {
+		return ErrMissingEOF
+	}
+
+	if t.StartXref < 0 || t.StartXref >= t.size {
+		return fmt.Errorf("%w: %d is not in [0, %d)", ErrStartxrefOutOfRange, t.StartXref, t.size)
+	}
+
+	head := make([]byte, 64)
+	n, err := t.ra.ReadAt(head, t.StartXref)
+	if n == 0 && err != nil {
+		return fmt.Errorf("unable to read at startxref: %w", err)
+	}
+	head = head[:n]
+
+	if bytes.HasPrefix(head, []byte("xref")) {
+		return nil
+	}
+	if l := bytes.SplitN(head, []byte("\n"), 2)[0]; isObjectHeader(string(l)) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q at %d", ErrInvalidXrefStart, head, t.StartXref)
+}
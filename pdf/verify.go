@@ -0,0 +1,112 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// XrefError describes an in-use cross-reference entry whose byte offset does not point to its object.
+// FoundNumber and FoundGeneration are -1 when no object header is found at the offset.
+type XrefError struct {
+	Entry           XrefEntry
+	FoundNumber     int64
+	FoundGeneration int
+}
+
+func (e XrefError) Error() string {
+	if e.FoundNumber < 0 {
+		return fmt.Sprintf("%d %d R: no object at %d", e.Entry.Number, e.Entry.Generation, e.Entry.ByteOffset)
+	}
+	return fmt.Sprintf(
+		"%d %d R: found %d %d obj at %d",
+		e.Entry.Number, e.Entry.Generation, e.FoundNumber, e.FoundGeneration, e.Entry.ByteOffset,
+	)
+}
+
+// VerifyXref checks the byte offset of every in-use entry points to "N G obj" of the same object number and generation.
+// Compressed objects are not checked since they have no byte offset.
+func (d *Document) VerifyXref() []XrefError {
+	var errs []XrefError
+	head := make([]byte, 64)
+	for _, ent := range d.entries {
+		if !ent.InUse || ent.Compressed {
+			continue
+		}
+
+		n, _ := d.ra.ReadAt(head, ent.ByteOffset)
+		number, generation := int64(-1), -1
+
+		// the header must start at the offset
+		if loc := objectHeaderPattern.FindSubmatchIndex(head[:n]); loc != nil && loc[0] == 0 {
+			number, _ = strconv.ParseInt(string(head[loc[2]:loc[3]]), 10, 64)
+			generation, _ = strconv.Atoi(string(head[loc[4]:loc[5]]))
+		}
+
+		if number != ent.Number || generation != ent.Generation {
+			errs = append(errs, XrefError{Entry: ent, FoundNumber: number, FoundGeneration: generation})
+		}
+	}
+	return errs
+}
+
+// LengthError describes a stream object whose /Length does not match the body.
+// Declared is -1 when /Length is missing or is not an integer.
+// Actual is the length up to endstream excluding the end-of-line marker before it.
+type LengthError struct {
+	Entry    XrefEntry
+	Declared int64
+	Actual   int64
+	// Err is set when the stream cannot be read
+	Err error
+}
+
+func (e LengthError) Error() string {
+	ref := fmt.Sprintf("%d %d R", e.Entry.Number, e.Entry.Generation)
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("%s: %v", ref, e.Err)
+	case e.Declared < 0:
+		return fmt.Sprintf("%s: no valid /Length but %d bytes found until endstream", ref, e.Actual)
+	}
+	return fmt.Sprintf("%s: /Length %d but %d bytes found until endstream", ref, e.Declared, e.Actual)
+}
+
+// VerifyStreamLengths checks /Length of every stream object is the length of the body up to endstream.
+// An indirect /Length is resolved. Objects which are not streams are skipped.
+func (d *Document) VerifyStreamLengths() []LengthError {
+	var errs []LengthError
+	for _, ent := range d.entries {
+		if !ent.InUse || ent.Compressed {
+			continue
+		}
+
+		// VerifyXref reports objects which cannot be read
+		dict, bodyOffset, err := readStreamHeader(d.ra, ent.ByteOffset)
+		if err != nil {
+			continue
+		}
+
+		declared := int64(-1)
+		if obj, ok := dict["Length"]; ok {
+			resolved, err := d.Resolve(obj)
+			if err != nil {
+				errs = append(errs, LengthError{Entry: ent, Err: fmt.Errorf("unable to resolve /Length: %w", err)})
+				continue
+			}
+			if n, ok := resolved.(PDFInt); ok && n >= 0 {
+				declared = int64(n)
+			}
+		}
+
+		// the declared length is right when it is followed by endstream even if the body contains endstream
+		actual, err := streamDataLength(d.ra, bodyOffset, declared)
+		if err != nil {
+			errs = append(errs, LengthError{Entry: ent, Declared: declared, Err: err})
+			continue
+		}
+		if declared != actual {
+			errs = append(errs, LengthError{Entry: ent, Declared: declared, Actual: actual})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,76 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const headerMarker = "%PDF-"
+
+// readHeader finds %PDF-x.y in the first 1024 bytes and returns its offset and the version.
+// 7.5.2 File Header
+func readHeader(ra io.ReaderAt) (int64, string, error) {
+	b := make([]byte, 1024+len(headerMarker)+8)
+	n, err := ra.ReadAt(b, 0)
+	if err != nil && err != io.EOF {
+		return 0, "", fmt.Errorf("unable to read the header: %w", err)
+	}
+	b = b[:n]
+
+	i := bytes.Index(b, []byte(headerMarker))
+	if i < 0 || i > 1024 {
+		return 0, "", errors.New("unable to find %PDF- header")
+	}
+
+	version := b[i+len(headerMarker):]
+	end := 0
+	for end < len(version) && (version[end] == '.' || ('0' <= version[end] && version[end] <= '9')) {
+		end++
+	}
+	if end == 0 {
+		return 0, "", errors.New("header has no version")
+	}
+	return int64(i), string(version[:end]), nil
+}
+
+// Version returns the version of the document.
+// /Version in the catalog takes precedence over the header if it is later.
+// 7.2.2 Version in the document catalog (PDF 1.4)
+func (d *Document) Version() (string, error) {
+	_, version, err := readHeader(d.ra)
+	if err != nil {
+		return "", err
+	}
+
+	catalog, err := d.Catalog()
+	if err != nil {
+		return "", err
+	}
+	obj, err := d.Resolve(catalog["Version"])
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve /Version: %w", err)
+	}
+	if v, ok := obj.(PDFName); ok && compareVersions(string(v), version) > 0 {
+		version = string(v)
+	}
+	return version, nil
+}
+
+// compareVersions compares versions such as 1.4 and 1.10 numerically.
+func compareVersions(a, b string) int {
+	parse := func(s string) (int, int) {
+		var major, minor int
+		fmt.Sscanf(s, "%d.%d", &major, &minor)
+		return major, minor
+	}
+	amajor, aminor := parse(a)
+	bmajor, bminor := parse(b)
+	switch {
+	case amajor != bmajor:
+		return amajor - bmajor
+	default:
+		return aminor - bminor
+	}
+}
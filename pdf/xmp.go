@@ -0,0 +1,122 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// XMP returns the raw XML of the metadata stream of the catalog.
+// 14.3.2 Metadata Streams
+func (d *Document) XMP() ([]byte, error) {
+	catalog, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	ref, ok := catalog["Metadata"].(PDFRef)
+	if !ok {
+		return nil, errors.New("catalog has no /Metadata")
+	}
+	_, b, err := d.readStream(ref)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read /Metadata: %w", err)
+	}
+	return b, nil
+}
+
+// XMPTitle returns dc:title in the XMP metadata.
+func (d *Document) XMPTitle() (string, error) {
+	values, err := d.xmpDublinCore("title")
+	if err != nil {
+		return "", err
+	}
+	if len(values) == 0 {
+		return "", nil
+	}
+	return values[0], nil
+}
+
+// XMPAuthor returns dc:creator in the XMP metadata. Multiple creators are joined with ", ".
+func (d *Document) XMPAuthor() (string, error) {
+	values, err := d.xmpDublinCore("creator")
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(values, ", "), nil
+}
+
+const dublinCoreNamespace = "http://purl.org/dc/elements/1.1/"
+
+// xmpDublinCore returns the rdf:li values (or the text) of the Dublin Core property.
+// The x-default item of a language alternative comes first.
+func (d *Document) xmpDublinCore(name string) ([]string, error) {
+	b, err := d.XMP()
+	if err != nil {
+		return nil, err
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	var values []string
+	var text strings.Builder
+	// depth is the depth inside the property or 0 outside
+	depth := 0
+	isDefault := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse XMP: %w", err)
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if depth > 0 {
+				depth++
+				if tok.Name.Local == "li" {
+					text.Reset()
+					isDefault = false
+					for _, attr := range tok.Attr {
+						if attr.Name.Local == "lang" && attr.Value == "x-default" {
+							isDefault = true
+						}
+					}
+				}
+			} else if tok.Name.Space == dublinCoreNamespace && tok.Name.Local == name {
+				depth = 1
+				text.Reset()
+			}
+		case xml.CharData:
+			if depth > 0 {
+				text.Write(tok)
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				continue
+			}
+			depth--
+			switch {
+			case tok.Name.Local == "li":
+				if isDefault {
+					values = append([]string{text.String()}, values...)
+				} else {
+					values = append(values, text.String())
+				}
+			case depth == 0:
+				// a simple property without rdf:li
+				if len(values) == 0 {
+					if s := strings.TrimSpace(text.String()); s != "" {
+						values = append(values, s)
+					}
+				}
+				return values, nil
+			}
+		}
+	}
+	return values, nil
+}
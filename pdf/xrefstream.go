@@ -0,0 +1,120 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// 7.5.8 Cross-Reference Streams
+// listXrefStreamEntries lists entries in a cross-reference stream and returns them
+// with the stream dictionary which also serves as the trailer.
+func listXrefStreamEntries(ra io.ReaderAt, offset int64) ([]XrefEntry, PDFDict, error) {
+	dict, data, err := readXrefStream(ra, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	size, ok := dict["Size"].(PDFInt)
+	if !ok {
+		return nil, nil, errors.New("xref stream must have /Size")
+	}
+
+	var widths [3]int
+	w, ok := dict["W"].(PDFArray)
+	if !ok || len(w) != len(widths) {
+		return nil, nil, errors.New("xref stream must have /W with 3 elements")
+	}
+	for i := range w {
+		n, ok := w[i].(PDFInt)
+		if !ok || n < 0 || n > 8 {
+			return nil, nil, fmt.Errorf("invalid /W element: %v", w[i])
+		}
+		widths[i] = int(n)
+	}
+
+	// pairs of the first object number and the number of entries
+	index := PDFArray{PDFInt(0), size}
+	if idx, ok := dict["Index"].(PDFArray); ok {
+		index = idx
+	}
+	if len(index)%2 != 0 {
+		return nil, nil, errors.New("xref stream /Index must have pairs of integers")
+	}
+
+	entryLen := widths[0] + widths[1] + widths[2]
+	if entryLen == 0 {
+		return nil, nil, errors.New("xref stream /W must not be all zero")
+	}
+
+	var entries []XrefEntry
+	for i := 0; i < len(index); i += 2 {
+		first, ok1 := index[i].(PDFInt)
+		count, ok2 := index[i+1].(PDFInt)
+		if !ok1 || !ok2 {
+			return nil, nil, errors.New("xref stream /Index must have pairs of integers")
+		}
+
+		for j := int64(0); j < int64(count); j++ {
+			if len(data) < entryLen {
+				return nil, nil, errors.New("xref stream is too short")
+			}
+
+			fields := [3]int64{1, 0, 0}
+			pos := 0
+			for k, width := range widths {
+				if width == 0 {
+					continue
+				}
+				fields[k] = readBigEndian(data[pos : pos+width])
+				pos += width
+			}
+			data = data[entryLen:]
+
+			entry := XrefEntry{Number: int64(first) + j}
+			switch fields[0] {
+			case 0:
+				entry.Generation = int(fields[2])
+			case 1:
+				entry.ByteOffset = fields[1]
+				entry.Generation = int(fields[2])
+				entry.InUse = true
+			case 2:
+				entry.InUse = true
+				entry.Compressed = true
+				entry.StreamNumber = fields[1]
+				entry.StreamIndex = int(fields[2])
+			default:
+				// 7.5.8.3: any other type shall be treated as a reference to the null object
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, dict, nil
+}
+
+func readXrefStream(ra io.ReaderAt, offset int64) (PDFDict, []byte, error) {
+	dict, raw, err := readStreamAt(ra, offset)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read xref stream: %w", err)
+	}
+	if typ, _ := dict["Type"].(PDFName); typ != "XRef" {
+		return nil, nil, fmt.Errorf("%w: xref stream must have /Type /XRef but got %q", ErrNotXref, typ)
+	}
+
+	data, err := DecodeStream(dict, raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to decode xref stream: %w", err)
+	}
+	return dict, data, nil
+}
+
+func readBigEndian(b []byte) int64 {
+	var n int64
+	for _, c := range b {
+		n = n<<8 | int64(c)
+	}
+	return n
+}